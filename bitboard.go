@@ -0,0 +1,137 @@
+package tictactoe
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Bitboard is a compact board representation for fast simulation and search.
+// It has one bitmask per mark, where bit i is set if Cells[i]
+// of the equivalent Board has that mark. A cell can't have both marks.
+//
+// The zero value is an empty board.
+type Bitboard struct {
+	X, O uint16
+}
+
+// fullBoard is the bitmask with all cells set.
+const fullBoard = 1<<9 - 1
+
+// lineMasks are the bitmasks of all 8 lines of 3 cells on a classic board:
+// 3 rows, 3 columns and 2 diagonals. They're the winning lines
+// of a board full of X marks.
+var lineMasks = func() (masks [8]uint16) {
+	var full Board
+	for i := range full.Cells {
+		full.Cells[i] = X
+	}
+	for i, l := range full.WinningLines() {
+		for _, m := range l.Cells {
+			masks[i] |= 1 << uint(m)
+		}
+	}
+	return masks
+}()
+
+// NewBitboard returns the bitboard equivalent of board b.
+func NewBitboard(b Board) Bitboard {
+	var bb Bitboard
+	for i, cell := range b.Cells {
+		switch cell {
+		case X:
+			bb.X |= 1 << uint(i)
+		case O:
+			bb.O |= 1 << uint(i)
+		}
+	}
+	return bb
+}
+
+// Board returns the Board equivalent of bitboard bb.
+func (bb Bitboard) Board() Board {
+	var b Board
+	for i := range b.Cells {
+		switch {
+		case bb.X&(1<<uint(i)) != 0:
+			b.Cells[i] = X
+		case bb.O&(1<<uint(i)) != 0:
+			b.Cells[i] = O
+		}
+	}
+	return b
+}
+
+// Apply a move to this board. Mark is either X or O.
+// If the move is not valid or not legal, the board is not modified and an error is returned.
+func (bb *Bitboard) Apply(move Move, mark State) error {
+	if err := move.Valid(); err != nil {
+		return err
+	}
+	bit := uint16(1) << uint(move)
+	// Check if the move is legal for this board configuration.
+	if (bb.X|bb.O)&bit != 0 {
+		return fmt.Errorf("that cell is already occupied")
+	}
+
+	switch mark {
+	case X:
+		bb.X |= bit
+	case O:
+		bb.O |= bit
+	default:
+		return fmt.Errorf("mark %v is neither X nor O", mark)
+	}
+	return nil
+}
+
+// Condition returns the condition of the board.
+// It's equivalent to Board.Condition.
+func (bb Bitboard) Condition() Condition {
+	x, o := bits.OnesCount16(bb.X), bits.OnesCount16(bb.O)
+	if x > o+1 || o > x+1 {
+		return Invalid
+	}
+	xLines, xShared := lines(bb.X)
+	oLines, oShared := lines(bb.O)
+
+	switch {
+	case xLines > 0 && oLines > 0:
+		return Invalid
+	case xLines > 0:
+		if x < o || xShared == 0 {
+			return Invalid
+		}
+		return XWon
+	case oLines > 0:
+		if o < x || oShared == 0 {
+			return Invalid
+		}
+		return OWon
+	case bb.X|bb.O == fullBoard:
+		return Tie
+	default:
+		return NotEnd
+	}
+}
+
+// lines returns the number of lines of 3 cells that are all set in mask,
+// and the cells that all of those lines share.
+func lines(mask uint16) (n int, shared uint16) {
+	shared = fullBoard
+	for _, l := range lineMasks {
+		if mask&l == l {
+			n++
+			shared &= l
+		}
+	}
+	return n, shared
+}
+
+// LegalMoves returns all legal moves on board bb, in increasing order.
+func (bb Bitboard) LegalMoves() []Move {
+	var moves []Move
+	for free := ^(bb.X | bb.O) & fullBoard; free != 0; free &= free - 1 {
+		moves = append(moves, Move(bits.TrailingZeros16(free)))
+	}
+	return moves
+}
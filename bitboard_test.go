@@ -0,0 +1,126 @@
+package tictactoe_test
+
+import (
+	"reflect"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+// TestBitboard checks that Bitboard agrees with Board
+// on every possible cell configuration.
+func TestBitboard(t *testing.T) {
+	for _, b := range allBoards() {
+		bb := ttt.NewBitboard(b)
+		if got := bb.Board(); got != b {
+			t.Fatalf("round trip: got\n%v\nwant\n%v", got, b)
+		}
+		if got, want := bb.Condition(), b.Condition(); got != want {
+			t.Errorf("board\n%v\ngot condition %v, want %v", b, got, want)
+		}
+		if got, want := bb.LegalMoves(), b.LegalMoves(); !reflect.DeepEqual(got, want) {
+			t.Errorf("board\n%v\ngot legal moves %v, want %v", b, got, want)
+		}
+	}
+}
+
+func TestBitboardApply(t *testing.T) {
+	var b ttt.Board
+	var bb ttt.Bitboard
+	for i, m := range []ttt.Move{4, 0, 8, 2, 1, 7, 6, 3, 5} {
+		mark := ttt.X
+		if i%2 == 1 {
+			mark = ttt.O
+		}
+		if err := b.Apply(m, mark); err != nil {
+			t.Fatal(err)
+		}
+		if err := bb.Apply(m, mark); err != nil {
+			t.Fatal(err)
+		}
+		if got := bb.Board(); got != b {
+			t.Fatalf("got\n%v\nwant\n%v", got, b)
+		}
+	}
+	if err := bb.Apply(4, ttt.O); err == nil {
+		t.Error("applied a move into an occupied cell, want error")
+	}
+	if err := bb.Apply(9, ttt.O); err == nil {
+		t.Error("applied an invalid move, want error")
+	}
+}
+
+func BenchmarkBoardCondition(b *testing.B) {
+	boards := allBoards()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = boards[i%len(boards)].Condition()
+	}
+}
+
+func BenchmarkBitboardCondition(b *testing.B) {
+	var boards []ttt.Bitboard
+	for _, board := range allBoards() {
+		boards = append(boards, ttt.NewBitboard(board))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = boards[i%len(boards)].Condition()
+	}
+}
+
+// BenchmarkBoardSearch measures a search of the full game tree using Board.
+func BenchmarkBoardSearch(b *testing.B) {
+	var search func(board ttt.Board, mark ttt.State) int
+	search = func(board ttt.Board, mark ttt.State) int {
+		if board.Condition() != ttt.NotEnd {
+			return 1
+		}
+		n := 1
+		for _, m := range board.LegalMoves() {
+			next := board
+			next.Apply(m, mark)
+			n += search(next, mark.Opponent())
+		}
+		return n
+	}
+	for i := 0; i < b.N; i++ {
+		if got, want := search(ttt.Board{}, ttt.X), 549946; got != want {
+			b.Fatalf("searched %v nodes, want %v", got, want)
+		}
+	}
+}
+
+// BenchmarkBitboardSearch measures a search of the full game tree using Bitboard.
+func BenchmarkBitboardSearch(b *testing.B) {
+	var search func(board ttt.Bitboard, mark ttt.State) int
+	search = func(board ttt.Bitboard, mark ttt.State) int {
+		if board.Condition() != ttt.NotEnd {
+			return 1
+		}
+		n := 1
+		for _, m := range board.LegalMoves() {
+			next := board
+			next.Apply(m, mark)
+			n += search(next, mark.Opponent())
+		}
+		return n
+	}
+	for i := 0; i < b.N; i++ {
+		if got, want := search(ttt.Bitboard{}, ttt.X), 549946; got != want {
+			b.Fatalf("searched %v nodes, want %v", got, want)
+		}
+	}
+}
+
+// allBoards returns all 3^9 possible cell configurations,
+// including ones that can't be reached in legal play.
+func allBoards() []ttt.Board {
+	boards := make([]ttt.Board, 19683)
+	for i := range boards {
+		for j, n := 0, i; j < len(boards[i].Cells); j, n = j+1, n/3 {
+			boards[i].Cells[j] = ttt.State(n % 3)
+		}
+	}
+	return boards
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+	"github.com/shurcooL/tictactoe/referee"
+)
+
+// playBatch plays n games one after another, with new players
+// for each game seeded like newPlayers(seed + 2*i) for the i'th game,
+// and reports outcome statistics when they're over.
+func playBatch(seed int64, opt referee.Options, n int) error {
+	var (
+		start        = time.Now()
+		x, o         ttt.Player
+		outcomes     = make(map[ttt.Condition]int)
+		terminations = make(map[record.Termination]int)
+		moves        int
+	)
+	for i := 0; i < n; i++ {
+		var err error
+		x, o, err = newPlayers(seed + 2*int64(i))
+		if err != nil {
+			return err
+		}
+		result, err := referee.Match(context.Background(), x, o, opt)
+		if err != nil {
+			return err
+		}
+		outcomes[result.Outcome()]++
+		terminations[result.Termination]++
+		moves += len(result.Moves)
+	}
+	elapsed := time.Since(start)
+
+	fmt.Printf("%v games in %v (%.0f games/s), %.1f moves per game on average.\n",
+		n, elapsed.Round(time.Microsecond), float64(n)/elapsed.Seconds(), float64(moves)/float64(n))
+	fmt.Println()
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	for _, c := range []struct {
+		text  string
+		count int
+	}{
+		{fmt.Sprintf("player X (%v) won", x.Name()), outcomes[ttt.XWon]},
+		{fmt.Sprintf("player O (%v) won", o.Name()), outcomes[ttt.OWon]},
+		{"tie", outcomes[ttt.Tie]},
+	} {
+		fmt.Fprintf(tw, "%v\t%v\t%.1f%%\t\n", c.text, c.count, 100*float64(c.count)/float64(n))
+	}
+	fmt.Fprintln(tw)
+	for t := record.Line; t <= record.Aborted; t++ {
+		if terminations[t] == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "by %v\t%v\t%.1f%%\t\n", t, terminations[t], 100*float64(terminations[t])/float64(n))
+	}
+	return tw.Flush()
+}
@@ -3,12 +3,20 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"html/template"
+	"log"
+	"net/url"
+	"strings"
 	"syscall/js"
+	"time"
 
 	"github.com/shurcooL/htmlg"
 	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+	"github.com/shurcooL/tictactoe/referee"
 	"golang.org/x/net/html"
 	"golang.org/x/net/html/atom"
 	"honnef.co/go/js/dom/v2"
@@ -16,13 +24,53 @@ import (
 
 var document = dom.GetWindow().Document().(dom.HTMLDocument)
 
-func displayGameStart(board ttt.Board, players [2]player, cellClick chan<- int) {
+func init() {
+	frontend = newBrowser
+	replay = replayInBrowser
+
+	// Let the page's query parameters choose a game record to replay,
+	// e.g., "?replay=game.json&replay-speed=500ms".
+	location := js.Global().Get("location")
+	query, err := url.ParseQuery(strings.TrimPrefix(location.Get("search").String(), "?"))
+	if err != nil {
+		log.Println("failed to parse query parameters:", err)
+		return
+	}
+	if name := query.Get("replay"); name != "" {
+		// Resolve relative URLs against the page's URL.
+		if u, err := url.Parse(location.Get("href").String()); err == nil {
+			if u, err := u.Parse(name); err == nil {
+				name = u.String()
+			}
+		}
+		flag.Set("replay", name)
+	}
+	if speed := query.Get("replay-speed"); speed != "" {
+		if err := flag.Set("replay-speed", speed); err != nil {
+			log.Println("invalid replay-speed query parameter:", err)
+		}
+	}
+}
+
+// browser is a display that renders the game as a web page.
+type browser struct {
+	players     [2]player
+	drawOffered bool // Whether a draw was offered this turn.
+}
+
+// player of the game and its mark.
+type player struct {
+	ttt.Player
+	Mark ttt.State // Mark is either X or O.
+}
+
+func newBrowser(cellClick chan<- int, commands chan<- ttt.Command) referee.Display {
 	// Wait for DOM to finish loading.
 	waitDOM()
 
 	document.SetTitle("Tic-Tac-Toe")
 
-	// When a board cell is clicked, send its [0, 9) index to cellClick channel.
+	// When a board cell is clicked, send its [0, width*height) index to cellClick channel.
 	js.Global().Set("CellClick", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
 		index := args[0].Int()
 		select {
@@ -31,39 +79,218 @@ func displayGameStart(board ttt.Board, players [2]player, cellClick chan<- int)
 		}
 		return nil
 	}))
+
+	// When a command button is clicked, send its command to commands channel.
+	js.Global().Set("Command", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		c := ttt.Command(args[0].Int())
+		select {
+		case commands <- c:
+		default:
+		}
+		return nil
+	}))
+
+	return &browser{}
 }
 
-func displayTurnStart(board ttt.Board, players [2]player, active player, condition ttt.Condition) {
-	// Draw page at start of turn.
+func (b *browser) GameStart(x, o ttt.Player) {
+	b.players = [2]player{{Player: x, Mark: ttt.X}, {Player: o, Mark: ttt.O}}
+}
+
+func (b *browser) TurnStart(t referee.Turn) {
+	b.drawOffered = false
+	b.drawTurn(t, "")
+}
+
+// drawTurn draws the page during turn t, with an optional status message.
+func (b *browser) drawTurn(t referee.Turn, message string) {
+	active := b.player(t.Mark)
 	_, isCellClicker := active.Player.(ttt.CellClicker)
-	document.Body().SetInnerHTML(htmlg.Render(page{Board: board, Turn: active.Mark, Clickable: isCellClicker, Condition: condition, Players: players}.Render()...))
+	p := page{Board: t.MNKBoard, Turn: t.Mark, Clickable: isCellClicker, Clock: t.Clock, Message: message, Players: b.players}
+	if _, ok := active.Player.(ttt.Commander); ok {
+		p.Commands = []ttt.Command{ttt.Resign}
+		if !b.drawOffered {
+			p.Commands = append(p.Commands, ttt.OfferDraw)
+		}
+	}
+	document.Body().SetInnerHTML(htmlg.Render(p.Render()...))
 }
 
-func displayTurnEnding(board ttt.Board, players [2]player, condition ttt.Condition) {
+func (b *browser) DrawOffer(t referee.Turn) {
+	b.drawOffered = true
+	opponent := b.player(t.Mark.Opponent())
+	p := page{Board: t.MNKBoard, Turn: opponent.Mark, Clock: t.Clock, Players: b.players,
+		Message: fmt.Sprintf("%v (%v) offers a draw.", b.player(t.Mark).Name(), t.Mark)}
+	if _, ok := opponent.Player.(ttt.Commander); ok {
+		p.Commands = []ttt.Command{ttt.AcceptDraw, ttt.DeclineDraw}
+	}
+	document.Body().SetInnerHTML(htmlg.Render(p.Render()...))
+}
+
+func (b *browser) DrawAnswer(t referee.Turn, accepted bool) {
+	if accepted {
+		// The game is over, which GameEnd draws.
+		return
+	}
+	b.drawTurn(t, fmt.Sprintf("%v (%v) declined the draw offer.", b.player(t.Mark.Opponent()).Name(), t.Mark.Opponent()))
+}
+
+// player returns the player with the given mark.
+func (b *browser) player(mark ttt.State) player {
+	if mark == ttt.O {
+		return b.players[1]
+	}
+	return b.players[0]
+}
+
+func (b *browser) TurnEnd(t referee.Turn) {
 	// Draw page after player finished turn.
-	document.Body().SetInnerHTML(htmlg.Render(page{Board: board, Condition: condition, Players: players}.Render()...))
+	document.Body().SetInnerHTML(htmlg.Render(page{Board: t.MNKBoard, Condition: t.MNKBoard.Condition(), Clock: t.Clock, Players: b.players}.Render()...))
 }
 
-func displayGameEnd(board ttt.Board, players [2]player, condition ttt.Condition) {
+func (b *browser) GameEnd(r referee.Result, err error) {
+	if err != nil {
+		// Draw page on error.
+		document.Body().SetInnerHTML(htmlg.Render(page{Board: r.MNKBoard, ErrorMessage: err.Error(), Players: b.players}.Render()...))
+		return
+	}
+	if r.Forfeit != nil {
+		// Draw page on forfeit.
+		message := fmt.Sprintf("%v (%v%v)", r.Forfeit, r.Outcome(), byTermination(r.Termination))
+		document.Body().SetInnerHTML(htmlg.Render(page{Board: r.MNKBoard, ErrorMessage: message, Players: b.players}.Render()...))
+		return
+	}
 	// Draw page at end of game.
-	document.Body().SetInnerHTML(htmlg.Render(page{Board: board, Condition: condition, Players: players}.Render()...))
-	select {}
+	document.Body().SetInnerHTML(htmlg.Render(page{Board: r.MNKBoard, Condition: r.Outcome(), Termination: r.Termination, Players: b.players}.Render()...))
+}
+
+// replayInBrowser replays a recorded game as a web page,
+// with controls to step through it.
+// It never returns unless the record can't be replayed.
+func replayInBrowser(rec record.Record, speed time.Duration) error {
+	r, err := newReplayer(rec)
+	if err != nil {
+		return err
+	}
+
+	// Wait for DOM to finish loading.
+	waitDOM()
+
+	document.SetTitle("Tic-Tac-Toe Replay")
+
+	// When a replay control is clicked, send its command to commands channel.
+	commands := make(chan string)
+	js.Global().Set("ReplayControl", js.FuncOf(func(_ js.Value, args []js.Value) interface{} {
+		select {
+		case commands <- args[0].String():
+		default:
+		}
+		return nil
+	}))
+
+	players := [2]player{{Player: recordedPlayer(rec.X.Name), Mark: ttt.X}, {Player: recordedPlayer(rec.O.Name), Mark: ttt.O}}
+	var playing bool
+	for {
+		// Draw page at the current move.
+		p := page{Board: r.game.MNKBoard(), Players: players, Replay: &replayControls{Status: r.LastMove(), Playing: playing}}
+		if r.AtEnd() {
+			p.Condition, p.Termination = rec.Result, rec.Termination
+		} else {
+			p.Turn = r.game.Turn()
+		}
+		document.Body().SetInnerHTML(htmlg.Render(p.Render()...))
+
+		var next <-chan time.Time
+		if playing {
+			next = time.After(speed)
+		}
+		select {
+		case cmd := <-commands:
+			switch cmd {
+			case "start":
+				r.Seek(0)
+			case "previous":
+				r.Step(-1)
+			case "play":
+				if !playing && r.AtEnd() {
+					r.Seek(0)
+				}
+				playing = !playing
+				continue
+			case "next":
+				r.Step(+1)
+			case "end":
+				r.Seek(r.game.Len())
+			}
+			playing = false
+		case <-next:
+			r.Step(+1)
+			playing = !r.AtEnd()
+		}
+	}
 }
 
-func displayError(board ttt.Board, players [2]player, err error) {
-	// Draw page on error.
-	document.Body().SetInnerHTML(htmlg.Render(page{Board: board, ErrorMessage: err.Error(), Players: players}.Render()...))
-	select {}
+// recordedPlayer is a player of a recorded game, known only by name.
+type recordedPlayer string
+
+func (p recordedPlayer) Name() string { return string(p) }
+
+func (recordedPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	return 0, fmt.Errorf("a recorded player can't play")
+}
+
+// replayControls renders the status and controls of a replay.
+type replayControls struct {
+	Status  string // Description of the current move.
+	Playing bool   // Whether the replay is being played back.
+}
+
+func (c replayControls) Render() []*html.Node {
+	control := func(cmd, text string) *html.Node {
+		return &html.Node{
+			Type: html.ElementNode, Data: atom.A.String(),
+			Attr: []html.Attribute{
+				{Key: atom.Style.String(), Val: `cursor: pointer; margin-left: 10px; margin-right: 10px;`},
+				{Key: atom.Onclick.String(), Val: fmt.Sprintf(`ReplayControl(%q);`, cmd)},
+			},
+			FirstChild: htmlg.Text(text),
+		}
+	}
+	play := "Play"
+	if c.Playing {
+		play = "Pause"
+	}
+	return []*html.Node{
+		style(
+			`text-align: center;`,
+			htmlg.Div(htmlg.Text(c.Status)),
+		),
+		style(
+			`text-align: center; margin-top: 10px;`,
+			htmlg.Div(
+				control("start", "« Start"),
+				control("previous", "‹ Previous"),
+				control("play", play),
+				control("next", "Next ›"),
+				control("end", "End »"),
+			),
+		),
+	}
 }
 
 // page renders the entire page body.
 type page struct {
-	Board        ttt.Board
+	Board        ttt.MNKBoard
 	Turn         ttt.State
 	Clickable    bool
+	Commands     []ttt.Command // Commands the player whose turn it is can give, as buttons.
 	Condition    ttt.Condition
+	Termination  record.Termination // How the game ended, if it's over.
+	Clock        referee.Clock      // Time the players have left, if there's a time control.
+	Message      string             // Status message, e.g., about a draw offer.
 	ErrorMessage string
 	Players      [2]player
+	Replay       *replayControls // Replay controls, if the page shows a replay.
 }
 
 func (p page) Render() []*html.Node {
@@ -77,54 +304,109 @@ func (p page) Render() []*html.Node {
 	case p.Condition != ttt.NotEnd:
 		statusMessage = style(
 			`line-height: 60px; text-align: center;`,
-			htmlg.Div(htmlg.Text(p.Condition.String())),
+			htmlg.Div(htmlg.Text(p.Condition.String()+byTermination(p.Termination))),
+		)
+	case p.Message != "":
+		statusMessage = style(
+			`line-height: 60px; text-align: center;`,
+			htmlg.Div(htmlg.Text(p.Message)),
 		)
 	default:
 		statusMessage = style(`height: 60px;`, htmlg.Div())
 	}
-	return []*html.Node{
+	nodes := []*html.Node{
 		style(
 			`text-align: center; margin-top: 50px;`,
 			htmlg.Div(
 				// Player X.
 				style(
 					`display: inline-block; width: 200px;`,
-					htmlg.Span(p.Players[0].Render(p.Turn)...),
+					htmlg.Span(append(p.Players[0].Render(p.Turn), p.clock(ttt.X)...)...),
 				),
 				// Board.
 				style(
 					`display: inline-block; margin-left: 30px; margin-right: 30px;`,
-					htmlg.Span(board{Board: p.Board, Clickable: p.Clickable}.Render()...),
+					htmlg.Span(board{MNKBoard: p.Board, Clickable: p.Clickable}.Render()...),
 				),
 				// Player O.
 				style(
 					`display: inline-block; width: 200px;`,
-					htmlg.Span(p.Players[1].Render(p.Turn)...),
+					htmlg.Span(append(p.Players[1].Render(p.Turn), p.clock(ttt.O)...)...),
 				),
 			),
 		),
 		statusMessage,
+	}
+	if len(p.Commands) > 0 {
+		nodes = append(nodes, commandButtons(p.Commands))
+	}
+	if p.Replay != nil {
+		nodes = append(nodes, p.Replay.Render()...)
+	}
+	return append(nodes,
 		// Give credit to Renee French for the Go gopher.
 		style(
 			`text-align: right; font-style: italic;`,
 			htmlg.Div(htmlg.Text("Go gopher by Renee French.")),
 		),
+	)
+}
+
+// commandButtons renders buttons that give commands to the player whose turn it is.
+func commandButtons(commands []ttt.Command) *html.Node {
+	div := style(`text-align: center;`, htmlg.Div())
+	for _, c := range commands {
+		div.AppendChild(&html.Node{
+			Type: html.ElementNode, Data: atom.Button.String(),
+			Attr: []html.Attribute{
+				{Key: atom.Style.String(), Val: `cursor: pointer; margin-left: 10px; margin-right: 10px;`},
+				{Key: atom.Onclick.String(), Val: fmt.Sprintf(`Command(%d);`, c)},
+			},
+			FirstChild: htmlg.Text(strings.Title(c.String())),
+		})
+	}
+	return div
+}
+
+// clock renders the time the player with the given mark has left,
+// if there's a time control.
+func (p page) clock(mark ttt.State) []*html.Node {
+	if p.Clock == (referee.Clock{}) {
+		return nil
+	}
+	return []*html.Node{
+		style(
+			`font-family: monospace;`,
+			htmlg.Div(htmlg.Text(formatClock(p.Clock.Remaining(mark)))),
+		),
 	}
 }
 
-// board renders a board.
+// board renders a board of any size.
+// The zero board is rendered as an empty classic board.
 type board struct {
-	ttt.Board
+	ttt.MNKBoard
 	Clickable bool
 }
 
 func (b board) Render() []*html.Node {
+	if b.Rules == (ttt.Rules{}) {
+		b.MNKBoard = ttt.Board{}.MNK()
+	}
+	// Highlight the cells of winning lines, if any.
+	winning := make([]bool, len(b.Cells))
+	for _, l := range b.WinningLines() {
+		for _, m := range l.Cells {
+			winning[m] = true
+		}
+	}
 	table := &html.Node{Data: atom.Table.String(), Type: html.ElementNode}
-	for row := 0; row < 3; row++ {
+	for row := 0; row < b.Height; row++ {
 		tr := &html.Node{Data: atom.Tr.String(), Type: html.ElementNode}
-		for col, cell := range b.Cells[3*row : 3*row+3] {
+		for col, cell := range b.Cells[b.Width*row : b.Width*row+b.Width] {
 			td := &html.Node{Data: atom.Td.String(), Type: html.ElementNode}
-			htmlg.AppendChildren(td, boardCell{State: cell, Clickable: b.Clickable, Index: 3*row + col}.Render()...)
+			index := b.Width*row + col
+			htmlg.AppendChildren(td, boardCell{State: cell, Clickable: b.Clickable, Winning: winning[index], Index: index}.Render()...)
 			tr.AppendChild(td)
 		}
 		table.AppendChild(tr)
@@ -138,12 +420,17 @@ func (b board) Render() []*html.Node {
 type boardCell struct {
 	ttt.State
 	Clickable bool
+	Winning   bool // Winning is set if the cell is part of a winning line.
 	Index     int
 }
 
 func (c boardCell) Render() []*html.Node {
+	backgroundColor := "#f4f4f4"
+	if c.Winning {
+		backgroundColor = "#c8e6c9"
+	}
 	cell := style(
-		`display: table-cell; width: 30px; height: 30px; text-align: center; vertical-align: middle; background-color: #f4f4f4;`,
+		`display: table-cell; width: 30px; height: 30px; text-align: center; vertical-align: middle; background-color: `+backgroundColor+`;`,
 		htmlg.Div(
 			htmlg.Text(c.String()),
 		),
@@ -5,131 +5,265 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"time"
 
 	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+	"github.com/shurcooL/tictactoe/referee"
 )
 
-import (
-	playerx "github.com/shurcooL/tictactoe/player/random"
-	// vs
-	playero "github.com/shurcooL/tictactoe/player/perfect"
+var (
+	xFlag        = flag.String("x", "random", "Player X, one of: "+playerNames()+".")
+	oFlag        = flag.String("o", "perfect", "Player O, one of: "+playerNames()+".")
+	xLevelFlag   = flag.String("x-difficulty", "", "If set, difficulty of player X, one of: easy, medium, hard.")
+	oLevelFlag   = flag.String("o-difficulty", "", "If set, difficulty of player O, one of: easy, medium, hard.")
+	rulesFlag    = flag.String("rules", "3,3,3", "Rules of the m,n,k-game as width,height,k, where k marks in a row win, e.g., 4,4,4 or 15,15,5. Both players must support them.")
+	listFlag     = flag.Bool("list", false, "List available players and exit.")
+	turnTimeFlag = flag.Duration("turn-time", 0, "Time each player gets to think per turn. Zero means 5s, or 1m in games with a human player, or no limit per turn if -time is set.")
+	minTurnFlag  = flag.Duration("min-turn", time.Second, "Minimum duration of a turn, so that the game can be followed.")
+	gamesFlag    = flag.Int("games", 1, "Number of games to play.")
+	seedFlag     = flag.Int64("seed", 0, "Seed for players' randomness. In the n'th game, counting from 0, player X uses seed+2n and player O uses seed+2n+1, so any game can be replayed with -seed set to the seed of its player X. If not set, a seed based on the current time is used.")
+	replayFlag   = flag.String("replay", "", "If set, replay the game record in this file or at this URL instead of playing.")
+	speedFlag    = flag.Duration("replay-speed", time.Second, "Time between moves when playing back a replay.")
+	batchFlag    = flag.Bool("batch", false, "Play -games games as fast as possible, without showing them or any artificial delays, and report outcome statistics.")
+
+	logFlag    = flag.String("log", "", "If set, write a JSON log of game events to this file.")
+	recordFlag = flag.String("record", "", "If set, save a record of the game to this file. With -games, a game number is added to the file name.")
+
+	timeFlag      = flag.Duration("time", 0, "If set, each player's total time for the game, e.g., 1m for blitz or 72h for correspondence-style play.")
+	incrementFlag = flag.Duration("increment", 0, "Time added to a player's clock after each of their moves (Fischer increment). Used with -time.")
+	delayFlag     = flag.Duration("delay", 0, "Time per move that doesn't count against a player's clock (Bronstein delay). Used with -time.")
 )
 
-// timePerTurn is the time each player gets to think per turn.
-const timePerTurn = 5 * time.Second
+// frontend creates the display that shows the game to the user.
+// Clicked board cells, if the display supports it, have their [0, width*height) index sent to cellClick,
+// and commands given to players, such as to resign, are sent to commands.
+var frontend = newTerminal
 
 func main() {
-	playerX := player{Mark: ttt.X}
-	playerO := player{Mark: ttt.O}
+	flag.Parse()
+
+	if *listFlag {
+		listPlayers()
+		return
+	}
 
 	var err error
-	playerX.Player, err = playerx.NewPlayer()
-	if err != nil {
-		log.Fatalln(fmt.Errorf("failed to initialize player X: %v", err))
+	if *replayFlag != "" {
+		err = runReplay()
+	} else {
+		err = run()
 	}
-	playerO.Player, err = playero.NewPlayer()
 	if err != nil {
-		log.Fatalln(fmt.Errorf("failed to initialize player O: %v", err))
+		log.Fatalln(err)
 	}
 
-	simulateGame([2]player{playerX, playerO})
-}
-
-type player struct {
-	ttt.Player
-	Mark ttt.State // Mark is either X or O.
+	if runtime.GOOS == "js" {
+		// Keep the page alive after the games are over.
+		select {}
+	}
 }
 
-// simulateGame simulates a playthrough of a game of tic-tac-toe with 2 players
-// until the end (Condition != ttt.NotEnd), or until an error happens.
-// players[0] always goes first.
-func simulateGame(players [2]player) {
-	// Start with an empty board.
-	var board ttt.Board
-	var condition ttt.Condition
+func run() error {
+	if *gamesFlag < 1 {
+		return fmt.Errorf("-games must be at least 1, got %v", *gamesFlag)
+	}
+	if *batchFlag && *recordFlag != "" {
+		return fmt.Errorf("-record can't be used with -batch")
+	}
+	rules, err := ttt.ParseRules(*rulesFlag)
+	if err != nil {
+		return fmt.Errorf("invalid -rules: %v", err)
+	}
+	seed := time.Now().UnixNano()
+	if isFlagSet("seed") {
+		seed = *seedFlag
+	}
+	// Check that the players can be created before starting.
+	x, o, err := newPlayers(seed)
+	if err != nil {
+		return err
+	}
 
-	// When a board cell is clicked, its [0, 9) index is sent to this channel.
+	// When a board cell is clicked, its [0, width*height) index is sent to this channel.
 	cellClick := make(chan int)
+	// When a player is given a command, such as to resign, it's sent to this channel.
+	commands := make(chan ttt.Command)
 
-	displayGameStart(board, players, cellClick)
+	var displays []referee.Display
+	if !*batchFlag {
+		displays = append(displays, frontend(cellClick, commands))
+	}
+	if *logFlag != "" {
+		f, err := os.Create(*logFlag)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		eventLog := referee.NewJSONLog(f)
+		displays = append(displays, eventLog)
+		defer func() {
+			if err := eventLog.Err(); err != nil {
+				log.Println("failed to write event log:", err)
+			}
+		}()
+	}
 
-	for i := 0; condition == ttt.NotEnd; i = (i + 1) % 2 {
-		displayTurnStart(board, players, players[i], condition)
+	opt := referee.Options{
+		TimePerTurn:     *turnTimeFlag,
+		MinTurnDuration: *minTurnFlag,
+		First:           ttt.X,
+		Rules:           rules,
+		CellClick:       cellClick,
+		Commands:        commands,
+		Displays:        displays,
+	}
+	if *timeFlag != 0 {
+		opt.TimeControl = referee.TimeControl{
+			Budget:    *timeFlag,
+			Increment: *incrementFlag,
+			Delay:     *delayFlag,
+		}
+	} else if opt.TimePerTurn == 0 && (isHuman(x) || isHuman(o)) {
+		opt.TimePerTurn = humanTimePerTurn
+	}
 
-		turnStart := time.Now()
+	if *batchFlag {
+		opt.MinTurnDuration = 0
+		return playBatch(seed, opt, *gamesFlag)
+	}
 
-		err := playerTurn(&board, players[i], cellClick)
+	var (
+		playerX, playerO ttt.Player
+		outcomes         = make(map[ttt.Condition]int)
+	)
+	for i := 0; i < *gamesFlag; i++ {
+		playerX, playerO, err = newPlayers(seed + 2*int64(i))
 		if err != nil {
-			displayError(board, players, err)
-			return
+			return err
 		}
+		result, err := playGame(playerX, playerO, opt, recordName(*recordFlag, i+1, *gamesFlag))
+		if err != nil {
+			return err
+		}
+		outcomes[result.Outcome()]++
+	}
+	if *gamesFlag > 1 {
+		fmt.Println()
+		fmt.Printf("%v games: player X (%v) won %v, player O (%v) won %v, %v tied.\n",
+			*gamesFlag, playerX.Name(), outcomes[ttt.XWon], playerO.Name(), outcomes[ttt.OWon], outcomes[ttt.Tie])
+	}
+	return nil
+}
 
-		condition = board.Condition()
-
-		// Enforce a minimum of 1 second per turn.
-		if untilTurnEnd := time.Second - time.Since(turnStart); untilTurnEnd > 0 {
-			displayTurnEnding(board, players, condition)
+func runReplay() error {
+	rec, err := loadRecord(*replayFlag)
+	if err != nil {
+		return err
+	}
+	return replay(rec, *speedFlag)
+}
 
-			time.Sleep(untilTurnEnd)
+// isFlagSet reports whether the named flag was set on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
 		}
-	}
+	})
+	return set
+}
+
+// humanTimePerTurn is the time per turn in games with human players,
+// unless set by flags.
+const humanTimePerTurn = time.Minute
 
-	// At this point, the game is over.
-	displayGameEnd(board, players, condition)
+// isHuman reports whether player p is controlled by a human,
+// which is the case if it makes its moves by clicking board cells.
+func isHuman(p ttt.Player) bool {
+	_, ok := p.(ttt.CellClicker)
+	return ok
 }
 
-// playerTurn gets the player p's move and applies it to board b.
-func playerTurn(b *ttt.Board, player player, cellClick <-chan int) error {
-	move, err := playerMove(*b, player, timePerTurn, cellClick)
-	if err != nil {
-		return fmt.Errorf("player %v (%s) failed to make a move: %v", player.Mark, player.Name(), err)
+// playGame plays a game of tic-tac-toe between players x and o
+// until the end, and saves its record to the named file if recordName isn't empty.
+func playGame(x, o ttt.Player, opt referee.Options, recordName string) (referee.Result, error) {
+	var recorder referee.Recorder
+	if recordName != "" {
+		opt.Displays = append(append([]referee.Display(nil), opt.Displays...), &recorder)
 	}
 
-	err = b.Apply(move, player.Mark)
+	result, err := referee.Match(context.Background(), x, o, opt)
 	if err != nil {
-		return fmt.Errorf("player %v (%s) made a move that isn't valid or isn't legal: %v", player.Mark, player.Name(), err)
+		return referee.Result{}, err
 	}
 
-	return nil
+	if recordName != "" {
+		rec, _ := recorder.Record()
+		err := saveRecord(recordName, rec)
+		if err != nil {
+			return referee.Result{}, err
+		}
+	}
+	return result, nil
 }
 
-// playerMove gets the player p's move, enforcing the timeout.
-func playerMove(b ttt.Board, p player, timeout time.Duration, cellClick <-chan int) (ttt.Move, error) {
-	type moveError struct {
-		ttt.Move
-		err error
+// recordName returns the name of the record file for the i'th of n games,
+// adding the game number before the extension of name if there's more than one game,
+// e.g., "game-2.json". It returns the empty string if name is empty.
+func recordName(name string, i, n int) string {
+	if name == "" || n == 1 {
+		return name
 	}
-	resultCh := make(chan moveError, 1)
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%v-%d%v", strings.TrimSuffix(name, ext), i, ext)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+// saveRecord saves game record rec to the named file.
+func saveRecord(name string, rec record.Record) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = record.Write(f, rec)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save game record: %v", err)
+	}
+	return f.Close()
+}
 
-	// We can't trust the player not to misbehave and just ignore the timeout, causing
-	// the game to stall. So we let it play inside a goroutine, and monitor ctx.Done()
-	// channel ourselves. No one wants a slowpoke to hold the game up! :) Also catch panics.
-	go func() {
-		defer func() {
-			if e := recover(); e != nil {
-				resultCh <- moveError{err: fmt.Errorf("panic: %v", e)}
-			}
-		}()
-		move, err := p.Play(ctx, b, p.Mark)
-		resultCh <- moveError{move, err}
-	}()
-
-	for {
-		select {
-		case result := <-resultCh:
-			return result.Move, result.err
-		case index := <-cellClick:
-			if p, ok := p.Player.(ttt.CellClicker); ok {
-				p.CellClick(index)
-			}
-		case <-ctx.Done():
-			return 0, fmt.Errorf("took more than allotted time of %v", timeout)
-		}
+// byTermination describes how a match ended, as a suffix to its outcome,
+// e.g., " by resignation". It's empty if the match ended on the board.
+func byTermination(t record.Termination) string {
+	switch t {
+	case record.Resignation:
+		return " by resignation"
+	case record.Agreement:
+		return " by agreement"
+	case record.Timeout:
+		return " on time"
+	case record.IllegalMove, record.Panic, record.PlayerError:
+		return " by forfeit"
+	default:
+		return ""
+	}
+}
+
+// formatClock formats time left on a clock as minutes and seconds,
+// e.g., "4:07.3", with hours added if needed.
+func formatClock(d time.Duration) string {
+	d = d.Round(100 * time.Millisecond)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%04.1f", d/time.Hour, d%time.Hour/time.Minute, (d % time.Minute).Seconds())
 	}
+	return fmt.Sprintf("%d:%04.1f", d/time.Minute, (d % time.Minute).Seconds())
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/registry"
+
+	// Players that can be chosen with the -x and -o flags.
+	_ "github.com/shurcooL/tictactoe/player/bad"
+	_ "github.com/shurcooL/tictactoe/player/human"
+	_ "github.com/shurcooL/tictactoe/player/perfect"
+	_ "github.com/shurcooL/tictactoe/player/random"
+)
+
+// newPlayers creates players X and O, as chosen by flags, for a single game.
+// Player X uses seed and player O uses seed+1 for their randomness.
+func newPlayers(seed int64) (x, o ttt.Player, err error) {
+	x, err = newPlayer(*xFlag, *xLevelFlag, seed, *batchFlag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize player X: %v", err)
+	}
+	o, err = newPlayer(*oFlag, *oLevelFlag, seed+1, *batchFlag)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize player O: %v", err)
+	}
+	return x, o, nil
+}
+
+// newPlayer creates the registered player with the given name
+// and difficulty, which may be empty for the player's default.
+// Players that support it use seed for their randomness.
+// If instant is true, the player must move without artificial delays.
+func newPlayer(name, difficulty string, seed int64, instant bool) (ttt.Player, error) {
+	e, ok := registry.Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown player %q, available players are: %v", name, playerNames())
+	}
+	opt := registry.Options{Instant: instant}
+	if e.Supports(registry.SeedOption) {
+		opt.Seed = &seed
+	}
+	if difficulty != "" {
+		d, err := registry.ParseDifficulty(difficulty)
+		if err != nil {
+			return nil, err
+		}
+		opt.Difficulty = d
+	}
+	return registry.New(name, opt)
+}
+
+// playerNames returns a comma-separated list of the names of all players.
+func playerNames() string {
+	return strings.Join(registry.Names(), ", ")
+}
+
+// listPlayers prints all players, with their descriptions and options.
+func listPlayers() {
+	for _, e := range registry.List() {
+		fmt.Printf("%v\t%v", e.Name, e.Description)
+		if len(e.Options) > 0 {
+			var options []string
+			for _, o := range e.Options {
+				options = append(options, o.String())
+			}
+			fmt.Printf(" Options: %v.", strings.Join(options, ", "))
+		}
+		fmt.Println()
+	}
+}
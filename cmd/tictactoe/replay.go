@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+)
+
+// replay shows a recorded game and lets the user step through it,
+// with speed as the time between moves during playback.
+var replay = replayInTerminal
+
+// loadRecord loads a game record from the named file,
+// or from a URL if name starts with "http://" or "https://".
+func loadRecord(name string) (record.Record, error) {
+	var r io.ReadCloser
+	if strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://") {
+		resp, err := http.Get(name)
+		if err != nil {
+			return record.Record{}, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return record.Record{}, fmt.Errorf("failed to get game record: %v", resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(name)
+		if err != nil {
+			return record.Record{}, err
+		}
+		r = f
+	}
+	defer r.Close()
+	return record.Read(r)
+}
+
+// replayer steps through a recorded game.
+type replayer struct {
+	rec  record.Record
+	game *ttt.Game // Game with all moves, positioned at the current move.
+}
+
+func newReplayer(rec record.Record) (*replayer, error) {
+	game, err := rec.Game()
+	if err != nil {
+		return nil, err
+	}
+	err = game.Seek(0)
+	if err != nil {
+		return nil, err
+	}
+	return &replayer{rec: rec, game: game}, nil
+}
+
+// Step moves delta moves forward, or backward if delta is negative,
+// stopping at the start or end of the game.
+// It reports whether it moved at all.
+func (r *replayer) Step(delta int) bool {
+	ply := r.game.Ply() + delta
+	if ply < 0 {
+		ply = 0
+	} else if ply > r.game.Len() {
+		ply = r.game.Len()
+	}
+	moved := ply != r.game.Ply()
+	r.game.Seek(ply)
+	return moved
+}
+
+// Seek jumps to the position after the first ply moves.
+func (r *replayer) Seek(ply int) error { return r.game.Seek(ply) }
+
+// AtEnd reports whether the replay is at the end of the game.
+func (r *replayer) AtEnd() bool { return r.game.Ply() == r.game.Len() }
+
+// LastMove describes the move that led to the current position,
+// e.g., "move 3 of 7: X in cell 5".
+func (r *replayer) LastMove() string {
+	ply := r.game.Ply()
+	if ply == 0 {
+		return fmt.Sprintf("start of game, %v moves", r.game.Len())
+	}
+	m := r.rec.Moves[ply-1]
+	return fmt.Sprintf("move %v of %v: %v in cell %v", ply, r.game.Len(), m.Mark, m.Move+1)
+}
+
+// replayInTerminal replays a recorded game in the terminal,
+// reading commands to step through it from stdin.
+func replayInTerminal(rec record.Record, speed time.Duration) error {
+	r, err := newReplayer(rec)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Tic-Tac-Toe Replay")
+	fmt.Println()
+	fmt.Printf("%v (X) vs %v (O), %v\n", rec.X.Name, rec.O.Name, rec.Start.Format("2006-01-02 15:04"))
+	show := func() {
+		fmt.Println()
+		fmt.Println(r.game.MNKBoard())
+		fmt.Println(r.LastMove())
+		if r.AtEnd() {
+			printOutcome(rec.X.Name, rec.O.Name, r.game.MNKBoard(), rec.Result, rec.Termination)
+		}
+	}
+	show()
+
+	stdin := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("[n]ext, [p]revious, [s]tart, [e]nd, [a]utoplay, move number or [q]uit: ")
+		if !stdin.Scan() {
+			fmt.Println()
+			return stdin.Err()
+		}
+		switch cmd := strings.TrimSpace(stdin.Text()); cmd {
+		case "", "n":
+			r.Step(+1)
+		case "p":
+			r.Step(-1)
+		case "s":
+			r.Seek(0)
+		case "e":
+			r.Seek(r.game.Len())
+		case "a":
+			if r.AtEnd() {
+				r.Seek(0)
+				show()
+			}
+			for !r.AtEnd() {
+				time.Sleep(speed)
+				r.Step(+1)
+				show()
+			}
+			continue
+		case "q":
+			return nil
+		default:
+			ply, err := strconv.Atoi(cmd)
+			if err != nil {
+				fmt.Printf("unknown command %q\n", cmd)
+				continue
+			}
+			if err := r.Seek(ply); err != nil {
+				fmt.Println(err)
+				continue
+			}
+		}
+		show()
+	}
+}
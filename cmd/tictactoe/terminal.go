@@ -1,42 +1,291 @@
-// +build !js
-
 package main
 
 import (
+	"bufio"
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 
 	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+	"github.com/shurcooL/tictactoe/referee"
 )
 
-func displayGameStart(board ttt.Board, players [2]player, cellClick chan<- int) {
+// terminal is a display that shows the game in a terminal.
+// Players that are ttt.CellClickers enter their moves on stdin,
+// and players that are ttt.Commanders can also resign and offer
+// or answer draw offers there.
+type terminal struct {
+	x, o      ttt.Player
+	cellClick chan<- int         // Entered moves are sent to this channel.
+	commands  chan<- ttt.Command // Entered commands are sent to this channel.
+
+	lines       <-chan string // Lines read from stdin while a prompt waits for them, once input is first needed.
+	turnEnded   chan struct{} // Closed when the turn or draw offer input is being entered for ends.
+	drawOffered bool          // Whether a draw was offered this turn.
+}
+
+func newTerminal(cellClick chan<- int, commands chan<- ttt.Command) referee.Display {
+	return &terminal{cellClick: cellClick, commands: commands}
+}
+
+func (t *terminal) GameStart(x, o ttt.Player) {
+	t.x, t.o = x, o
 	fmt.Println("Tic-Tac-Toe")
 	fmt.Println()
-	fmt.Printf("%v (X) vs %v (O)\n", players[0].Name(), players[1].Name())
+	fmt.Printf("%v (X) vs %v (O)\n", x.Name(), o.Name())
 }
 
-func displayTurnStart(board ttt.Board, players [2]player, active player, condition ttt.Condition) {
+func (t *terminal) TurnStart(turn referee.Turn) {
 	fmt.Println()
-	fmt.Println(board)
+	fmt.Println(turn.MNKBoard)
+	if turn.Clock != (referee.Clock{}) {
+		fmt.Printf("clock: X %v, O %v\n", formatClock(turn.Clock.X), formatClock(turn.Clock.O))
+	}
+
+	t.drawOffered = false
+	t.startMove(turn)
+}
+
+// startMove starts reading the move of the player whose turn it is
+// from stdin, if it's a ttt.CellClicker.
+func (t *terminal) startMove(turn referee.Turn) {
+	p := t.player(turn.Mark)
+	if _, ok := p.(ttt.CellClicker); !ok {
+		return
+	}
+	_, commander := p.(ttt.Commander)
+	t.turnEnded = make(chan struct{})
+	go t.enterMove(turn.MNKBoard, turn.Mark, commander, !t.drawOffered, t.input(), t.turnEnded)
 }
 
-func displayTurnEnding(board ttt.Board, players [2]player, condition ttt.Condition) {}
+func (t *terminal) TurnEnd(turn referee.Turn) {
+	t.endTurn()
+}
 
-func displayGameEnd(board ttt.Board, players [2]player, condition ttt.Condition) {
+func (t *terminal) DrawOffer(turn referee.Turn) {
+	t.endTurn()
+	t.drawOffered = true
+	opponent := turn.Mark.Opponent()
+	fmt.Printf("player %v offers player %v a draw.\n", turn.Mark, opponent)
+	if _, ok := t.player(opponent).(ttt.Commander); ok {
+		t.turnEnded = make(chan struct{})
+		go t.enterDrawAnswer(opponent, t.input(), t.turnEnded)
+	}
+}
+
+func (t *terminal) DrawAnswer(turn referee.Turn, accepted bool) {
+	t.endTurn()
+	if accepted {
+		fmt.Printf("player %v accepted the draw offer.\n", turn.Mark.Opponent())
+		return
+	}
+	fmt.Printf("player %v declined the draw offer.\n", turn.Mark.Opponent())
+	t.startMove(turn)
+}
+
+func (t *terminal) GameEnd(r referee.Result, err error) {
+	t.endTurn()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println()
-	fmt.Println(board)
+	fmt.Println(r.MNKBoard)
 	fmt.Println()
-	switch condition {
+	if r.Forfeit != nil {
+		fmt.Println(r.Forfeit)
+	}
+	printOutcome(t.x.Name(), t.o.Name(), r.MNKBoard, r.Outcome(), r.Termination)
+}
+
+// player returns the player with the given mark.
+func (t *terminal) player(mark ttt.State) ttt.Player {
+	if mark == ttt.O {
+		return t.o
+	}
+	return t.x
+}
+
+// endTurn stops waiting for a move or an answer to be entered, if it was.
+func (t *terminal) endTurn() {
+	if t.turnEnded != nil {
+		close(t.turnEnded)
+		t.turnEnded = nil
+	}
+}
+
+// input returns lines read from stdin,
+// starting to read them on first use.
+// Lines entered when no prompt is waiting for them,
+// such as during the opponent's turn, are discarded.
+func (t *terminal) input() <-chan string {
+	if t.lines == nil {
+		lines := make(chan string)
+		go func() {
+			stdin := bufio.NewScanner(os.Stdin)
+			for stdin.Scan() {
+				select {
+				case lines <- stdin.Text():
+				default:
+					// No prompt is waiting for it, e.g., it was typed ahead during the opponent's turn.
+				}
+			}
+			close(lines)
+		}()
+		t.lines = lines
+	}
+	return t.lines
+}
+
+// enterMove prompts for the move of the player with the given mark
+// on board b until a legal one is entered, and sends it to t.cellClick.
+// If commander is true, the player can instead enter "resign",
+// or "draw" if offerDraw is true, which is sent to t.commands.
+// It gives up when turnEnded is closed or there's no more input.
+func (t *terminal) enterMove(b ttt.MNKBoard, mark ttt.State, commander, offerDraw bool, lines <-chan string, turnEnded <-chan struct{}) {
+	moves := fmt.Sprintf("1-%v, or a1-%v", b.Width*b.Height, cellName(b.Rules, ttt.Move(b.Width*b.Height-1)))
+	prompt := fmt.Sprintf("player %v, enter your move (%v): ", mark, moves)
+	switch {
+	case commander && offerDraw:
+		prompt = fmt.Sprintf("player %v, enter your move (%v), resign or draw: ", mark, moves)
+	case commander:
+		prompt = fmt.Sprintf("player %v, enter your move (%v) or resign: ", mark, moves)
+	}
+	for {
+		fmt.Print(prompt)
+		line, ok := readLine(lines, turnEnded)
+		if !ok {
+			return
+		}
+		var command ttt.Command
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "resign":
+			command = ttt.Resign
+		case "draw":
+			command = ttt.OfferDraw
+		}
+		if command == ttt.OfferDraw && !offerDraw {
+			fmt.Println("a draw can only be offered once per turn")
+			continue
+		}
+		if command != 0 && commander {
+			select {
+			case t.commands <- command:
+			case <-turnEnded:
+			}
+			return
+		}
+		move, err := parseMove(line, b.Rules)
+		if err == nil && b.Cells[move] != ttt.F {
+			err = fmt.Errorf("cell %v is already occupied", move+1)
+		}
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		select {
+		case t.cellClick <- int(move):
+			return
+		case <-turnEnded:
+			return
+		}
+	}
+}
+
+// enterDrawAnswer prompts the player with the given mark to accept
+// or decline a draw offer, and sends the answer to t.commands.
+// It gives up when turnEnded is closed or there's no more input.
+func (t *terminal) enterDrawAnswer(mark ttt.State, lines <-chan string, turnEnded <-chan struct{}) {
+	for {
+		fmt.Printf("player %v, accept the draw offer (y/n)? ", mark)
+		line, ok := readLine(lines, turnEnded)
+		if !ok {
+			return
+		}
+		var answer ttt.Command
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y", "yes":
+			answer = ttt.AcceptDraw
+		case "n", "no":
+			answer = ttt.DeclineDraw
+		default:
+			fmt.Printf("%q is not y or n\n", line)
+			continue
+		}
+		select {
+		case t.commands <- answer:
+		case <-turnEnded:
+		}
+		return
+	}
+}
+
+// readLine reads a line from lines. ok is false if turnEnded
+// is closed or there's no more input first.
+func readLine(lines <-chan string, turnEnded <-chan struct{}) (line string, ok bool) {
+	select {
+	case line, ok = <-lines:
+	case <-turnEnded:
+	}
+	if !ok {
+		fmt.Println()
+	}
+	return line, ok
+}
+
+// parseMove parses a move on a board with rules r, entered as a cell number
+// from 1 to width*height, or as coordinates from a1 to, e.g., c3 on a classic board,
+// where the letter is the column and the number is the row, and a1 is the top left cell.
+func parseMove(s string, r ttt.Rules) (ttt.Move, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	cells := r.Width * r.Height
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 1 || n > cells {
+			return 0, fmt.Errorf("cell number %v is out of range [1, %v]", n, cells)
+		}
+		return ttt.Move(n - 1), nil
+	}
+	if len(s) >= 2 && s[0] >= 'a' && int(s[0]-'a') < r.Width && s[1] >= '1' && s[1] <= '9' {
+		row, err := strconv.Atoi(s[1:])
+		if err == nil && row <= r.Height {
+			col := int(s[0] - 'a')
+			return ttt.Move(r.Width*(row-1) + col), nil
+		}
+	}
+	return 0, fmt.Errorf("%q is not a cell number from 1 to %v or coordinates from a1 to %v", s, cells, cellName(r, ttt.Move(cells-1)))
+}
+
+// cellName returns the coordinates of cell m on a board with rules r,
+// as accepted by parseMove, e.g., "b2" for the center of a classic board.
+func cellName(r ttt.Rules, m ttt.Move) string {
+	return fmt.Sprintf("%c%v", 'a'+int(m)%r.Width, int(m)/r.Width+1)
+}
+
+// printOutcome prints the outcome of a game between players named x and o
+// that ended on board b, and the winning lines, if any.
+func printOutcome(x, o string, b ttt.MNKBoard, outcome ttt.Condition, t record.Termination) {
+	switch outcome {
 	case ttt.XWon:
-		fmt.Printf("player X (%v) won!\n", players[0].Name())
+		fmt.Printf("player X (%v) won%v!\n", x, byTermination(t))
 	case ttt.OWon:
-		fmt.Printf("player O (%v) won!\n", players[1].Name())
+		fmt.Printf("player O (%v) won%v!\n", o, byTermination(t))
 	case ttt.Tie:
-		fmt.Println("game ended in a tie.")
+		fmt.Printf("game ended in a tie%v.\n", byTermination(t))
 	default:
-		fmt.Println(condition)
+		fmt.Println(b.Condition())
+	}
+	for _, l := range b.WinningLines() {
+		fmt.Printf("%v line through cells %v.\n", l.Direction, cellNumbers(l.Cells))
 	}
 }
 
-func displayError(board ttt.Board, players [2]player, err error) {
-	fmt.Println(err)
+// cellNumbers formats board cell indices as 1-based cell numbers.
+func cellNumbers(cells []ttt.Move) string {
+	var ns []string
+	for _, c := range cells {
+		ns = append(ns, fmt.Sprint(c+1))
+	}
+	return strings.Join(ns, ", ")
 }
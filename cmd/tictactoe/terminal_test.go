@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestParseMove(t *testing.T) {
+	fourByFour := ttt.Rules{Width: 4, Height: 4, K: 4}
+	tests := []struct {
+		in      string
+		rules   ttt.Rules
+		want    ttt.Move
+		wantErr bool
+	}{
+		{in: "5", rules: ttt.Classic, want: 4},
+		{in: "b2", rules: ttt.Classic, want: 4},
+		{in: "B2 ", rules: ttt.Classic, want: 4},
+		{in: "0", rules: ttt.Classic, wantErr: true},
+		{in: "10", rules: ttt.Classic, wantErr: true},
+		{in: "d1", rules: ttt.Classic, wantErr: true},
+		{in: "a4", rules: ttt.Classic, wantErr: true},
+		{in: "a4", rules: fourByFour, want: 12},
+		{in: "16", rules: fourByFour, want: 15},
+	}
+	for _, tc := range tests {
+		got, err := parseMove(tc.in, tc.rules)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("parseMove(%q, %v): got error %v, want error: %v", tc.in, tc.rules, err, tc.wantErr)
+			continue
+		}
+		if err == nil && got != tc.want {
+			t.Errorf("parseMove(%q, %v): got %v, want %v", tc.in, tc.rules, got, tc.want)
+		}
+	}
+}
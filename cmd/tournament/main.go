@@ -0,0 +1,177 @@
+// tournament plays a round-robin tournament between tic-tac-toe players.
+//
+// Every player plays every other player for a number of games,
+// alternating colors, and the standings and crosstable are printed
+// when all games are over.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/rating"
+	"github.com/shurcooL/tictactoe/referee"
+	"github.com/shurcooL/tictactoe/registry"
+	"github.com/shurcooL/tictactoe/tournament"
+
+	// Players that can enter the tournament. Human players can't,
+	// since they'd wait for board cells to be clicked.
+	_ "github.com/shurcooL/tictactoe/player/bad"
+	_ "github.com/shurcooL/tictactoe/player/perfect"
+	_ "github.com/shurcooL/tictactoe/player/random"
+)
+
+var (
+	playersFlag     = flag.String("players", strings.Join(registry.Names(), ","), "Comma-separated list of players to enter.")
+	gamesFlag       = flag.Int("games", 2, "Number of games each pair of players plays.")
+	concurrencyFlag = flag.Int("concurrency", 0, "Maximum number of games played at once. Zero means the number of CPUs.")
+	turnTimeFlag    = flag.Duration("turn-time", 0, "Time each player gets to think per turn. Zero means 5s, or no limit per turn if -time is set.")
+	timeFlag        = flag.Duration("time", 0, "If set, each player's total time for a game.")
+	incrementFlag   = flag.Duration("increment", 0, "Time added to a player's clock after each of their moves (Fischer increment). Used with -time.")
+	delayFlag       = flag.Duration("delay", 0, "Time per move that doesn't count against a player's clock (Bronstein delay). Used with -time.")
+	formatFlag      = flag.String("format", "text", "Output format of the standings, one of: text, csv, json.")
+	quietFlag       = flag.Bool("q", false, "Don't report the result of each game as it ends.")
+	seedFlag        = flag.Int64("seed", 0, "Seed for players' randomness. In the n'th game, counting from 0, player X uses seed+2n and player O uses seed+2n+1, so any game can be replayed with cmd/tictactoe and -seed set to the seed of its player X. If not set, a seed based on the current time is used.")
+	ratingsFlag     = flag.String("ratings", "", "If set, update the players' ratings in this file with the tournament's games. With -format text, the ratings are printed too.")
+)
+
+func main() {
+	flag.Parse()
+
+	err := run()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run() error {
+	var write func(tournament.Results) error
+	switch *formatFlag {
+	case "text":
+		write = func(r tournament.Results) error { return r.WriteText(os.Stdout) }
+	case "csv":
+		write = func(r tournament.Results) error { return r.WriteCSV(os.Stdout) }
+	case "json":
+		write = func(r tournament.Results) error { return r.WriteJSON(os.Stdout) }
+	default:
+		return fmt.Errorf("unknown format %q, want one of: text, csv, json", *formatFlag)
+	}
+
+	var entrants []tournament.Entrant
+	for _, name := range strings.Split(*playersFlag, ",") {
+		name := strings.TrimSpace(name)
+		e, ok := registry.Lookup(name)
+		if !ok {
+			return fmt.Errorf("unknown player %q, available players are: %v", name, strings.Join(registry.Names(), ", "))
+		}
+		// Players that support it move without artificial delays,
+		// so that games don't take longer than needed,
+		// and are seeded, so that games can be replayed.
+		instant, seeded := e.Supports(registry.InstantOption), e.Supports(registry.SeedOption)
+		entrants = append(entrants, tournament.Entrant{
+			Name: name,
+			New: func(seed int64) (ttt.Player, error) {
+				opt := registry.Options{Instant: instant}
+				if seeded {
+					opt.Seed = &seed
+				}
+				return registry.New(name, opt)
+			},
+		})
+	}
+	if len(entrants) < 2 {
+		return fmt.Errorf("a tournament needs at least 2 players, got %v", len(entrants))
+	}
+
+	opt := tournament.Options{
+		Games:       *gamesFlag,
+		Concurrency: *concurrencyFlag,
+		TimePerTurn: *turnTimeFlag,
+		Seed:        time.Now().UnixNano(),
+	}
+	if isFlagSet("seed") {
+		opt.Seed = *seedFlag
+	}
+	if *timeFlag != 0 {
+		opt.TimeControl = referee.TimeControl{
+			Budget:    *timeFlag,
+			Increment: *incrementFlag,
+			Delay:     *delayFlag,
+		}
+	}
+	if !*quietFlag {
+		opt.Progress = func(g tournament.Game) {
+			r := g.Result
+			fmt.Fprintf(os.Stderr, "%v (X) vs %v (O): %v by %v%v\n", g.X, g.O, r.Outcome(), r.Termination, seeds(r))
+		}
+	}
+
+	results, err := tournament.Run(context.Background(), entrants, opt)
+	if err != nil {
+		return err
+	}
+	err = write(results)
+	if err != nil {
+		return err
+	}
+
+	if *ratingsFlag != "" {
+		return updateRatings(*ratingsFlag, results)
+	}
+	return nil
+}
+
+// seeds formats the seeds of the players in a game result, if they're known.
+func seeds(r referee.Result) string {
+	var s []string
+	if r.XSeed != nil {
+		s = append(s, fmt.Sprintf("X %v", *r.XSeed))
+	}
+	if r.OSeed != nil {
+		s = append(s, fmt.Sprintf("O %v", *r.OSeed))
+	}
+	if len(s) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (seeds: %v)", strings.Join(s, ", "))
+}
+
+// isFlagSet reports whether the named flag was set on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// updateRatings updates the ratings in the named file
+// with the games of a tournament, as a single rating period.
+func updateRatings(name string, results tournament.Results) error {
+	table, err := rating.Load(name)
+	if err != nil {
+		return err
+	}
+	var games []rating.Game
+	for _, g := range results.Games {
+		games = append(games, rating.NewGame(g.X, g.O, g.Result))
+	}
+	table.Update(games)
+	err = table.Save(name)
+	if err != nil {
+		return err
+	}
+	if *formatFlag == "text" {
+		fmt.Println()
+		return table.WriteText(os.Stdout)
+	}
+	return nil
+}
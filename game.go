@@ -0,0 +1,176 @@
+package tictactoe
+
+import (
+	"fmt"
+	"time"
+)
+
+// Ply is a single move made by one of the players.
+type Ply struct {
+	Mark State     // Mark is either X or O.
+	Move Move      // Move that was made.
+	Time time.Time // Time when the move was made.
+}
+
+// Game of tic-tac-toe, or of another m,n,k-game, with full move history.
+// It enforces that players take alternating turns,
+// and supports undoing, redoing and jumping to any ply.
+//
+// The zero value is a new game of tic-tac-toe where X moves first.
+type Game struct {
+	rules Rules // Rules of the game. Zero value means Classic.
+	first State // Mark of the player that moves first. F means X.
+
+	plies []Ply   // All plies, including undone ones that can be redone.
+	ply   int     // Number of plies currently applied.
+	cells []State // Cells of the board with the first ply plies applied. Made on first use.
+}
+
+// NewGame creates a new game of tic-tac-toe on an empty board.
+// first is the mark of the player that moves first, either X or O.
+func NewGame(first State) *Game {
+	return &Game{first: first}
+}
+
+// NewMNKGame creates a new m,n,k-game with rules r on an empty board.
+// first is the mark of the player that moves first, either X or O.
+func NewMNKGame(r Rules, first State) (*Game, error) {
+	if err := r.Valid(); err != nil {
+		return nil, err
+	}
+	return &Game{rules: r, first: first}, nil
+}
+
+// Rules returns the rules of the game.
+func (g *Game) Rules() Rules {
+	if g.rules == (Rules{}) {
+		return Classic
+	}
+	return g.rules
+}
+
+// First returns the mark of the player that moves first.
+func (g *Game) First() State {
+	if g.first == F {
+		return X
+	}
+	return g.first
+}
+
+// Turn returns the mark of the player whose turn it is to move.
+func (g *Game) Turn() State {
+	if g.ply%2 == 0 {
+		return g.First()
+	}
+	return g.First().Opponent()
+}
+
+// Board returns the current board of a game of tic-tac-toe.
+// It panics if the game isn't played with Classic rules, see MNKBoard.
+func (g *Game) Board() Board {
+	if r := g.Rules(); r != Classic {
+		panic(fmt.Sprintf("tictactoe: Board of a game with %v rules", r))
+	}
+	var b Board
+	copy(b.Cells[:], g.cells)
+	return b
+}
+
+// MNKBoard returns the current board.
+func (g *Game) MNKBoard() MNKBoard {
+	b := g.board()
+	b.Cells = append([]State(nil), b.Cells...)
+	return b
+}
+
+// board returns the current board, which shares its cells with g.
+func (g *Game) board() MNKBoard {
+	r := g.Rules()
+	if g.cells == nil {
+		g.cells = make([]State, r.Width*r.Height)
+	}
+	return MNKBoard{Rules: r, Cells: g.cells}
+}
+
+// Condition returns the condition of the current board.
+func (g *Game) Condition() Condition {
+	return g.board().Condition()
+}
+
+// Play makes a move for the player with the given mark at the current time.
+// See Apply.
+func (g *Game) Play(mark State, move Move) error {
+	return g.Apply(Ply{Mark: mark, Move: move, Time: time.Now()})
+}
+
+// Apply applies ply p to the current board.
+// It must be p.Mark's turn, the game must not be over,
+// and the move must be valid and legal.
+// Any undone plies are discarded and can no longer be redone.
+func (g *Game) Apply(p Ply) error {
+	if c := g.Condition(); c != NotEnd {
+		return fmt.Errorf("game is over: %v", c)
+	}
+	if turn := g.Turn(); p.Mark != turn {
+		return fmt.Errorf("it's player %v's turn, not %v's", turn, p.Mark)
+	}
+	b := g.board()
+	if err := b.Apply(p.Move, p.Mark); err != nil {
+		return err
+	}
+	g.plies = append(g.plies[:g.ply], p)
+	g.ply++
+	return nil
+}
+
+// Ply returns the number of plies currently applied.
+func (g *Game) Ply() int {
+	return g.ply
+}
+
+// Len returns the total number of plies in the game,
+// including undone plies that can be redone.
+func (g *Game) Len() int {
+	return len(g.plies)
+}
+
+// History returns the plies currently applied, in order.
+func (g *Game) History() []Ply {
+	return append([]Ply(nil), g.plies[:g.ply]...)
+}
+
+// Undo undoes the last applied ply.
+func (g *Game) Undo() error {
+	if g.ply == 0 {
+		return fmt.Errorf("no plies to undo")
+	}
+	g.cells[g.plies[g.ply-1].Move] = F
+	g.ply--
+	return nil
+}
+
+// Redo reapplies the last undone ply.
+func (g *Game) Redo() error {
+	if g.ply == len(g.plies) {
+		return fmt.Errorf("no plies to redo")
+	}
+	p := g.plies[g.ply]
+	g.cells[p.Move] = p.Mark
+	g.ply++
+	return nil
+}
+
+// Seek jumps to the position after the first ply plies,
+// undoing or redoing plies as needed. ply must be in range [0, Len()].
+func (g *Game) Seek(ply int) error {
+	if ply < 0 || ply > len(g.plies) {
+		return fmt.Errorf("ply %v is out of range [0, %v]", ply, len(g.plies))
+	}
+	for g.ply > ply {
+		g.Undo()
+	}
+	for g.ply < ply {
+		g.Redo()
+	}
+	return nil
+}
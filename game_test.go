@@ -0,0 +1,128 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestGame(t *testing.T) {
+	g := ttt.NewGame(ttt.O)
+	if got, want := g.Turn(), ttt.O; got != want {
+		t.Fatalf("got turn %v, want %v", got, want)
+	}
+	if err := g.Play(ttt.X, 4); err == nil {
+		t.Error("X moved out of turn, want error")
+	}
+	for _, p := range []struct {
+		mark ttt.State
+		move ttt.Move
+	}{{ttt.O, 0}, {ttt.X, 4}, {ttt.O, 1}, {ttt.X, 8}} {
+		if err := g.Play(p.mark, p.move); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.Play(ttt.O, 4); err == nil {
+		t.Error("O moved into an occupied cell, want error")
+	}
+
+	// Take back X's last move, and play something else instead.
+	if err := g.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Board().Cells[8], ttt.F; got != want {
+		t.Errorf("got cell 8 %v after undo, want %v", got, want)
+	}
+	if err := g.Redo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Redo(); err == nil {
+		t.Error("redo past the end, want error")
+	}
+	if err := g.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Play(ttt.X, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Len(), 4; got != want {
+		t.Errorf("got len %v after playing over an undone ply, want %v", got, want)
+	}
+	if err := g.Play(ttt.O, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Play(ttt.X, 6); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Condition(), ttt.XWon; got != want {
+		t.Fatalf("got condition %v, want %v", got, want)
+	}
+	if err := g.Play(ttt.O, 3); err == nil {
+		t.Error("O moved after the game ended, want error")
+	}
+
+	// Replay the game from the start.
+	if err := g.Seek(0); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Board(), (ttt.Board{}); got != want {
+		t.Errorf("got board\n%v\nafter seeking to start, want empty", got)
+	}
+	if err := g.Seek(2); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Turn(), ttt.O; got != want {
+		t.Errorf("got turn %v after seeking to ply 2, want %v", got, want)
+	}
+	if err := g.Seek(g.Len()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(g.History()), 6; got != want {
+		t.Errorf("got %v plies in history, want %v", got, want)
+	}
+	if err := g.Seek(7); err == nil {
+		t.Error("seek past the end, want error")
+	}
+}
+
+func TestMNKGame(t *testing.T) {
+	if _, err := ttt.NewMNKGame(ttt.Rules{Width: 3, Height: 3, K: 4}, ttt.X); err == nil {
+		t.Error("got no error for rules where no line fits, want error")
+	}
+
+	g, err := ttt.NewMNKGame(ttt.Rules{Width: 4, Height: 4, K: 4}, ttt.X)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// X fills the top row while O plays below it.
+	for i, m := range []ttt.Move{0, 4, 1, 5, 2, 6} {
+		mark := ttt.X
+		if i%2 == 1 {
+			mark = ttt.O
+		}
+		if err := g.Play(mark, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := g.Condition(), ttt.NotEnd; got != want {
+		t.Errorf("got condition %v after 3 in a row, want %v", got, want)
+	}
+	if err := g.Play(ttt.X, 16); err == nil {
+		t.Error("X moved off the board, want error")
+	}
+	if err := g.Play(ttt.X, 3); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.Condition(), ttt.XWon; got != want {
+		t.Errorf("got condition %v after 4 in a row, want %v", got, want)
+	}
+
+	// The board is a copy that doesn't change with the game.
+	b := g.MNKBoard()
+	if err := g.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b.Cells[3], ttt.X; got != want {
+		t.Errorf("got cell 3 %v in board from before undo, want %v", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package tictactoe
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// NumIndices is the number of distinct board indices, 3^9.
+const NumIndices = 19683
+
+// Index returns a perfect index of board b, a compact key in range [0, NumIndices)
+// that is unique to its cell configuration. The index is the cells read
+// as a base-3 number with Cells[0] as the least significant digit, where
+// F, X and O are the digits 0, 1 and 2.
+func (b Board) Index() int {
+	var i int
+	for c := len(b.Cells) - 1; c >= 0; c-- {
+		i = 3*i + int(b.Cells[c])
+	}
+	return i
+}
+
+// BoardFromIndex returns the board with index i. It's the inverse of Board.Index.
+func BoardFromIndex(i int) (Board, error) {
+	if i < 0 || i >= NumIndices {
+		return Board{}, fmt.Errorf("index %v is out of range [0, %v)", i, NumIndices)
+	}
+	var b Board
+	for c := range b.Cells {
+		b.Cells[c] = State(i % 3)
+		i /= 3
+	}
+	return b, nil
+}
+
+// Zobrist is a table of random keys for Zobrist hashing of boards.
+// The hash of a board is the XOR of the keys of all of its marks,
+// so it can be updated incrementally as moves are applied.
+type Zobrist struct {
+	keys [9][2]uint64 // keys[m][mark-1] is the key of mark in cell m.
+}
+
+// NewZobrist creates a table of keys generated from seed.
+// Tables created from the same seed are identical.
+func NewZobrist(seed int64) *Zobrist {
+	r := rand.New(rand.NewSource(seed))
+	var z Zobrist
+	for m := range z.keys {
+		for mark := range z.keys[m] {
+			z.keys[m][mark] = r.Uint64()
+		}
+	}
+	return &z
+}
+
+// Key returns the key of a mark in cell m. XOR it into a hash
+// to update it when that mark is placed in or removed from cell m.
+// m must be valid, and mark is either X or O.
+func (z *Zobrist) Key(m Move, mark State) uint64 {
+	return z.keys[m][mark-1]
+}
+
+// Hash returns the Zobrist hash of board b.
+func (z *Zobrist) Hash(b Board) uint64 {
+	var h uint64
+	for m, cell := range b.Cells {
+		if cell == F {
+			continue
+		}
+		h ^= z.Key(Move(m), cell)
+	}
+	return h
+}
+
+// HashedBoard is a board that keeps its Zobrist hash
+// up to date as moves are applied.
+type HashedBoard struct {
+	Board
+	hash uint64
+	z    *Zobrist
+}
+
+// NewHashedBoard creates a hashed board with the same cells as board b,
+// using the keys of z.
+func (z *Zobrist) NewHashedBoard(b Board) HashedBoard {
+	return HashedBoard{Board: b, hash: z.Hash(b), z: z}
+}
+
+// Apply a move to this board and update its hash. Mark is either X or O.
+// If the move is not valid or not legal, the board is not modified and an error is returned.
+func (hb *HashedBoard) Apply(move Move, mark State) error {
+	if mark != X && mark != O {
+		return fmt.Errorf("mark %v is neither X nor O", mark)
+	}
+	if err := hb.Board.Apply(move, mark); err != nil {
+		return err
+	}
+	hb.hash ^= hb.z.Key(move, mark)
+	return nil
+}
+
+// Hash returns the Zobrist hash of the board.
+func (hb HashedBoard) Hash() uint64 {
+	return hb.hash
+}
@@ -0,0 +1,68 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestBoardIndex(t *testing.T) {
+	for i := 0; i < ttt.NumIndices; i++ {
+		b, err := ttt.BoardFromIndex(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.Index(); got != i {
+			t.Fatalf("board\n%v\ngot index %v, want %v", b, got, i)
+		}
+	}
+	for _, i := range []int{-1, ttt.NumIndices} {
+		if _, err := ttt.BoardFromIndex(i); err == nil {
+			t.Errorf("BoardFromIndex(%v): got nil error, want non-nil", i)
+		}
+	}
+}
+
+func TestZobrist(t *testing.T) {
+	z := ttt.NewZobrist(1)
+	if got, want := ttt.NewZobrist(1).Hash(ttt.Board{Cells: [9]ttt.State{4: ttt.X}}), z.Hash(ttt.Board{Cells: [9]ttt.State{4: ttt.X}}); got != want {
+		t.Errorf("tables created from the same seed gave different hashes %x and %x", got, want)
+	}
+
+	hb := z.NewHashedBoard(ttt.Board{})
+	if got := hb.Hash(); got != 0 {
+		t.Errorf("got hash %x for empty board, want 0", got)
+	}
+	for i, m := range []ttt.Move{4, 0, 8, 2, 1, 7} {
+		mark := ttt.X
+		if i%2 == 1 {
+			mark = ttt.O
+		}
+		if err := hb.Apply(m, mark); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := hb.Hash(), z.Hash(hb.Board); got != want {
+			t.Errorf("after move %v: got incremental hash %x, want %x", m, got, want)
+		}
+	}
+	before := hb.Hash()
+	if err := hb.Apply(4, ttt.O); err == nil {
+		t.Error("applied a move into an occupied cell, want error")
+	}
+	if got := hb.Hash(); got != before {
+		t.Errorf("illegal move changed the hash from %x to %x", before, got)
+	}
+
+	// Hashes of different reachable positions shouldn't collide.
+	seen := make(map[uint64]ttt.Board)
+	for _, b := range allBoards() {
+		if b.Validate() != nil {
+			continue
+		}
+		h := z.Hash(b)
+		if other, ok := seen[h]; ok {
+			t.Fatalf("boards\n%v\nand\n%v\nhave the same hash %x", b, other, h)
+		}
+		seen[h] = b
+	}
+}
@@ -0,0 +1,95 @@
+package tictactoe
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the state as "X" or "O", or null for a free cell.
+func (s State) MarshalJSON() ([]byte, error) {
+	switch s {
+	case F:
+		return []byte("null"), nil
+	case X:
+		return []byte(`"X"`), nil
+	case O:
+		return []byte(`"O"`), nil
+	default:
+		return nil, fmt.Errorf("invalid state %d", s)
+	}
+}
+
+// UnmarshalJSON decodes a state encoded by MarshalJSON.
+func (s *State) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "null":
+		*s = F
+	case `"X"`:
+		*s = X
+	case `"O"`:
+		*s = O
+	default:
+		return fmt.Errorf("invalid state %s", data)
+	}
+	return nil
+}
+
+// conditionNames are the JSON names of conditions.
+var conditionNames = map[Condition]string{
+	NotEnd:  "in_progress",
+	XWon:    "x_won",
+	OWon:    "o_won",
+	Tie:     "tie",
+	Invalid: "invalid",
+}
+
+// MarshalJSON encodes the condition as one of "in_progress",
+// "x_won", "o_won", "tie" or "invalid".
+func (c Condition) MarshalJSON() ([]byte, error) {
+	name, ok := conditionNames[c]
+	if !ok {
+		return nil, fmt.Errorf("invalid condition %d", c)
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON decodes a condition encoded by MarshalJSON.
+func (c *Condition) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid condition %s", data)
+	}
+	for cond, n := range conditionNames {
+		if n == name {
+			*c = cond
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid condition %q", name)
+}
+
+// jsonBoard is the JSON representation of a board.
+type jsonBoard struct {
+	Cells []State `json:"cells"`
+}
+
+// MarshalJSON encodes the board as an object with a "cells" array
+// of 9 states in row major order. For example:
+//
+//	{"cells":["X","O",null,"X",null,null,"O",null,null]}
+func (b Board) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonBoard{Cells: b.Cells[:]})
+}
+
+// UnmarshalJSON decodes a board encoded by MarshalJSON.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	var v jsonBoard
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v.Cells) != len(b.Cells) {
+		return fmt.Errorf("board has %v cells, want %v", len(v.Cells), len(b.Cells))
+	}
+	copy(b.Cells[:], v.Cells)
+	return nil
+}
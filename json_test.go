@@ -0,0 +1,71 @@
+package tictactoe_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestJSON(t *testing.T) {
+	type result struct {
+		Board     ttt.Board     `json:"board"`
+		Turn      ttt.State     `json:"turn"`
+		Winner    ttt.State     `json:"winner"`
+		LastMove  ttt.Move      `json:"last_move"`
+		Condition ttt.Condition `json:"condition"`
+	}
+	v := result{
+		Board: ttt.Board{
+			Cells: [9]ttt.State{
+				ttt.X, ttt.O, ttt.F,
+				ttt.X, ttt.F, ttt.F,
+				ttt.X, ttt.O, ttt.F,
+			},
+		},
+		Turn:      ttt.O,
+		Winner:    ttt.X,
+		LastMove:  6,
+		Condition: ttt.XWon,
+	}
+	const want = `{"board":{"cells":["X","O",null,"X",null,null,"X","O",null]},"turn":"O","winner":"X","last_move":6,"condition":"x_won"}`
+
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	var v2 result
+	err = json.Unmarshal([]byte(`{"board":{"cells":["X","O",null,"X",null,null,"X","O",null]},"turn":"O","winner":"X","last_move":6,"condition":"x_won"}`), &v2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(v2, v) {
+		t.Errorf("got %+v, want %+v", v2, v)
+	}
+
+	// A null state must decode as a free cell, even over a non-zero value.
+	v2.Winner = ttt.O
+	if err := json.Unmarshal([]byte(`{"winner":null}`), &v2); err != nil {
+		t.Fatal(err)
+	}
+	if v2.Winner != ttt.F {
+		t.Errorf("got winner %v, want free", v2.Winner)
+	}
+
+	for _, s := range []string{
+		`{"board":{"cells":["X"]}}`,
+		`{"turn":"x"}`,
+		`{"turn":1}`,
+		`{"condition":"won"}`,
+		`{"condition":1}`,
+	} {
+		if err := json.Unmarshal([]byte(s), new(result)); err == nil {
+			t.Errorf("Unmarshal(%s): got nil error, want non-nil", s)
+		}
+	}
+}
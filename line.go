@@ -0,0 +1,127 @@
+package tictactoe
+
+// Direction of a line on the board.
+type Direction uint8
+
+// Directions of a line on the board.
+const (
+	Horizontal   Direction = iota // Along a row, left to right.
+	Vertical                      // Along a column, top to bottom.
+	Diagonal                      // Top-left to bottom-right.
+	AntiDiagonal                  // Top-right to bottom-left.
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Horizontal:
+		return "horizontal"
+	case Vertical:
+		return "vertical"
+	case Diagonal:
+		return "diagonal"
+	case AntiDiagonal:
+		return "anti-diagonal"
+	default:
+		panic("unreachable")
+	}
+}
+
+// step returns the (row, column) step to the next cell of a line in direction d.
+func (d Direction) step() (dr, dc int) {
+	switch d {
+	case Horizontal:
+		return 0, 1
+	case Vertical:
+		return 1, 0
+	case Diagonal:
+		return 1, 1
+	case AntiDiagonal:
+		return 1, -1
+	default:
+		panic("unreachable")
+	}
+}
+
+// Line is a line of cells on the board that all have the same mark.
+type Line struct {
+	Mark      State  // Mark is either X or O.
+	Cells     []Move // Cells is the board cell indices that make up the line, in order.
+	Direction Direction
+}
+
+// WinningLines returns all lines of 3 marks in a row on board b.
+// It returns no lines if neither player has 3 marks in a row.
+//
+// A single move can complete more than one line at once,
+// so the winner of a game may have multiple winning lines.
+func (b Board) WinningLines() []Line {
+	return b.mnk().WinningLines()
+}
+
+// WinningLines returns all runs of K or more marks in a row on board b.
+// Each line includes all cells of its run, so it may be longer than K.
+// It returns no lines if neither player has K marks in a row.
+//
+// Lines are ordered by their first cell, and lines that start
+// in the same cell by direction.
+func (b MNKBoard) WinningLines() []Line {
+	var lines []Line
+	for r := 0; r < b.Height; r++ {
+		for c := 0; c < b.Width; c++ {
+			mark := b.Cells[b.Width*r+c]
+			if mark == F {
+				continue
+			}
+			for d := Horizontal; d <= AntiDiagonal; d++ {
+				dr, dc := d.step()
+				// Only count from the start of each run of marks.
+				if b.at(r-dr, c-dc) == mark {
+					continue
+				}
+				n := 1
+				for b.at(r+n*dr, c+n*dc) == mark {
+					n++
+				}
+				if n < b.K {
+					continue
+				}
+				l := Line{Mark: mark, Direction: d}
+				for i := 0; i < n; i++ {
+					l.Cells = append(l.Cells, Move(b.Width*(r+i*dr)+c+i*dc))
+				}
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
+
+// hasLines reports whether players X and O have K marks in a row on board b.
+// Unlike WinningLines, it doesn't allocate.
+func (b MNKBoard) hasLines() (x, o bool) {
+	for r := 0; r < b.Height; r++ {
+		for c := 0; c < b.Width; c++ {
+			mark := b.Cells[b.Width*r+c]
+			if mark == F || (mark == X && x) || (mark == O && o) {
+				continue
+			}
+			for d := Horizontal; d <= AntiDiagonal; d++ {
+				dr, dc := d.step()
+				// Skip lines that would run off the board.
+				if r+(b.K-1)*dr >= b.Height || c+(b.K-1)*dc < 0 || c+(b.K-1)*dc >= b.Width {
+					continue
+				}
+				i, step := b.Width*r+c, b.Width*dr+dc
+				n := 1
+				for n < b.K && b.Cells[i+n*step] == mark {
+					n++
+				}
+				if n == b.K {
+					x, o = x || mark == X, o || mark == O
+					break
+				}
+			}
+		}
+	}
+	return x, o
+}
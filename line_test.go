@@ -0,0 +1,48 @@
+package tictactoe_test
+
+import (
+	"reflect"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestBoardWinningLines(t *testing.T) {
+	// X completed a row and a column with a single move in the corner.
+	b := ttt.Board{
+		Cells: [9]ttt.State{
+			ttt.X, ttt.X, ttt.X,
+			ttt.X, ttt.O, ttt.O,
+			ttt.X, ttt.O, ttt.O,
+		},
+	}
+	want := []ttt.Line{
+		{Mark: ttt.X, Cells: []ttt.Move{0, 1, 2}, Direction: ttt.Horizontal},
+		{Mark: ttt.X, Cells: []ttt.Move{0, 3, 6}, Direction: ttt.Vertical},
+	}
+	if got := b.WinningLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := (ttt.Board{}).WinningLines(); len(got) != 0 {
+		t.Errorf("got %v for empty board, want no lines", got)
+	}
+}
+
+func TestMNKBoardWinningLines(t *testing.T) {
+	b, err := ttt.NewMNKBoard(ttt.Rules{Width: 5, Height: 5, K: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []ttt.Move{4, 8, 12, 16, 20} {
+		if err := b.Apply(m, ttt.O); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []ttt.Line{
+		{Mark: ttt.O, Cells: []ttt.Move{4, 8, 12, 16, 20}, Direction: ttt.AntiDiagonal},
+	}
+	if got := b.WinningLines(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,171 @@
+package tictactoe
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Rules of an m,n,k-game, a generalization of tic-tac-toe
+// where players take turns placing their marks on a Width by Height board,
+// and the first player to get K marks in a row wins.
+type Rules struct {
+	Width  int `json:"width"`  // Number of columns on the board.
+	Height int `json:"height"` // Number of rows on the board.
+	K      int `json:"k"`      // Number of marks in a row needed to win.
+}
+
+// Classic are the rules of tic-tac-toe: a 3x3 board, 3 in a row wins.
+var Classic = Rules{Width: 3, Height: 3, K: 3}
+
+// Valid reports if the rules are valid.
+func (r Rules) Valid() error {
+	if r.Width < 1 || r.Height < 1 {
+		return fmt.Errorf("board size %dx%d is not valid", r.Width, r.Height)
+	}
+	if r.K < 1 || (r.K > r.Width && r.K > r.Height) {
+		return fmt.Errorf("win length %d doesn't fit on a %dx%d board", r.K, r.Width, r.Height)
+	}
+	return nil
+}
+
+// ValidMove reports if the move is valid for a board with rules r,
+// i.e., if it's in the range [0, Width*Height).
+// A valid move may not be legal depending on the board configuration.
+func (r Rules) ValidMove(m Move) error {
+	n := r.Width * r.Height
+	ok := m >= 0 && int(m) < n
+	if !ok {
+		return fmt.Errorf("move %v is out of range [0, %d)", m, n)
+	}
+	return nil
+}
+
+func (r Rules) String() string {
+	return fmt.Sprintf("%d,%d,%d", r.Width, r.Height, r.K)
+}
+
+// ParseRules parses rules in the format of Rules.String,
+// e.g., "4,4,4" for a 4x4 board where 4 in a row wins.
+func ParseRules(s string) (Rules, error) {
+	var r Rules
+	_, err := fmt.Sscanf(s, "%d,%d,%d", &r.Width, &r.Height, &r.K)
+	if err != nil || r.String() != s {
+		return Rules{}, fmt.Errorf("rules %q aren't in width,height,k format", s)
+	}
+	return r, r.Valid()
+}
+
+// MNKBoard is a board for an m,n,k-game.
+// Board is the classic 3x3 special case, and is implemented with it.
+//
+// Games with other than Classic rules are played with NewMNKGame,
+// by players that implement MNKPlayer.
+//
+// In JSON, an m,n,k-game board is encoded as an object with its rules
+// and cells, e.g., {"width":4,"height":4,"k":4,"cells":[...]}.
+type MNKBoard struct {
+	Rules
+
+	// Cells is a Height x Width matrix in row major order.
+	// Cells[Width*r + c] is the cell in the r'th row and c'th column.
+	// Move m will affect Cells[m].
+	Cells []State `json:"cells"`
+}
+
+// NewMNKBoard creates an empty board for an m,n,k-game with rules r.
+func NewMNKBoard(r Rules) (MNKBoard, error) {
+	if err := r.Valid(); err != nil {
+		return MNKBoard{}, err
+	}
+	return MNKBoard{
+		Rules: r,
+		Cells: make([]State, r.Width*r.Height),
+	}, nil
+}
+
+// MNK returns a copy of the classic board b as an m,n,k-game board
+// with Classic rules.
+func (b Board) MNK() MNKBoard {
+	return MNKBoard{
+		Rules: Classic,
+		Cells: append([]State(nil), b.Cells[:]...),
+	}
+}
+
+// Apply a move to this board. Mark is either X or O.
+// If the move is not valid or not legal, the board is not modified and an error is returned.
+func (b *MNKBoard) Apply(move Move, mark State) error {
+	if err := b.ValidMove(move); err != nil {
+		return err
+	}
+	// Check if the move is legal for this board configuration.
+	if b.Cells[move] != F {
+		return fmt.Errorf("that cell is already occupied")
+	}
+
+	b.Cells[move] = mark
+	return nil
+}
+
+// Condition returns the condition of the board.
+func (b MNKBoard) Condition() Condition {
+	var xs, os int // Number of X and O marks.
+	for _, cell := range b.Cells {
+		// Count without branching, which is much faster on unpredictable boards.
+		xs += int(cell & X)
+		os += int(cell&O) >> 1
+	}
+	x, o := b.hasLines()
+
+	// Check that the position can be reached in legal play, like Validate does.
+	switch {
+	case xs > os+1 || os > xs+1, x && o, x && xs < os, o && os < xs:
+		return Invalid
+	case (x && xs >= 2*b.K) || (o && os >= 2*b.K):
+		// Winning lines that couldn't have been completed by a single move
+		// take at least 2K marks, so they're only looked for when the winner
+		// has that many. On a classic board, no player ever does.
+		if b.Validate() != nil {
+			return Invalid
+		}
+	}
+
+	switch {
+	case x:
+		return XWon
+	case o:
+		return OWon
+	case xs+os == len(b.Cells):
+		return Tie
+	default:
+		return NotEnd
+	}
+}
+
+// at returns the state of the cell in the r'th row and c'th column,
+// or F if that cell is outside the board.
+func (b MNKBoard) at(r, c int) State {
+	if r < 0 || r >= b.Height || c < 0 || c >= b.Width {
+		return F
+	}
+	return b.Cells[b.Width*r+c]
+}
+
+func (b MNKBoard) String() string {
+	var buf bytes.Buffer
+	separator := strings.Repeat("───┼", b.Width-1) + "───"
+	for r := 0; r < b.Height; r++ {
+		if r > 0 {
+			fmt.Fprintln(&buf)
+			fmt.Fprintln(&buf, separator)
+		}
+		for c := 0; c < b.Width; c++ {
+			if c > 0 {
+				fmt.Fprint(&buf, "│")
+			}
+			fmt.Fprintf(&buf, " %v ", b.Cells[b.Width*r+c])
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,149 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestMNKBoardCondition(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules ttt.Rules
+		cells string
+		want  ttt.Condition
+	}{
+		{
+			name:  "4x4 row",
+			rules: ttt.Rules{Width: 4, Height: 4, K: 4},
+			cells: "" +
+				"XXXX" +
+				"OO.." +
+				"O..." +
+				"....",
+			want: ttt.XWon,
+		},
+		{
+			name:  "4x4 three in a row",
+			rules: ttt.Rules{Width: 4, Height: 4, K: 4},
+			cells: "" +
+				"XXX." +
+				"OO.." +
+				"O..." +
+				"....",
+			want: ttt.NotEnd,
+		},
+		{
+			name:  "5x5 anti-diagonal",
+			rules: ttt.Rules{Width: 5, Height: 5, K: 4},
+			cells: "" +
+				"X...." +
+				"X...O" +
+				"X..O." +
+				"..O.." +
+				".O...",
+			want: ttt.OWon,
+		},
+		{
+			name:  "5x5 column",
+			rules: ttt.Rules{Width: 5, Height: 5, K: 4},
+			cells: "" +
+				"....." +
+				"..X.O" +
+				"..X.O" +
+				"..X.O" +
+				"..X..",
+			want: ttt.XWon,
+		},
+		{
+			name:  "line doesn't wrap around",
+			rules: ttt.Rules{Width: 4, Height: 4, K: 4},
+			cells: "" +
+				"..XX" +
+				"XX.." +
+				"OO.O" +
+				"....",
+			want: ttt.NotEnd,
+		},
+		{
+			name:  "5x5 disjoint lines",
+			rules: ttt.Rules{Width: 5, Height: 5, K: 3},
+			cells: "" +
+				"XXX.." +
+				"O.O.O" +
+				"XXX.." +
+				"O.O.." +
+				".....",
+			want: ttt.Invalid,
+		},
+		{
+			name:  "4x3 tie",
+			rules: ttt.Rules{Width: 4, Height: 3, K: 3},
+			cells: "" +
+				"XXOO" +
+				"OOXX" +
+				"XXOO",
+			want: ttt.Tie,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := ttt.NewMNKBoard(tc.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range tc.cells {
+				if c == '.' {
+					continue
+				}
+				mark := ttt.X
+				if c == 'O' {
+					mark = ttt.O
+				}
+				if err := b.Apply(ttt.Move(i), mark); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := b.Condition(); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestMNKBoardClassic checks that converting a Board with MNK
+// keeps its condition, on every possible cell configuration.
+func TestMNKBoardClassic(t *testing.T) {
+	for _, b := range allBoards() {
+		if got, want := b.MNK().Condition(), b.Condition(); got != want {
+			t.Errorf("board\n%v\ngot %v, want %v", b, got, want)
+		}
+	}
+}
+
+func TestRulesValid(t *testing.T) {
+	for _, r := range []ttt.Rules{ttt.Classic, {4, 4, 4}, {5, 5, 4}, {15, 15, 5}, {7, 1, 5}} {
+		if err := r.Valid(); err != nil {
+			t.Errorf("%v: got error %v, want nil", r, err)
+		}
+	}
+	for _, r := range []ttt.Rules{{0, 3, 3}, {3, 3, 0}, {3, 3, 4}, {-1, -1, 1}} {
+		if err := r.Valid(); err == nil {
+			t.Errorf("%v: got nil error, want non-nil", r)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	for _, r := range []ttt.Rules{ttt.Classic, {4, 4, 4}, {15, 15, 5}} {
+		got, err := ttt.ParseRules(r.String())
+		if err != nil || got != r {
+			t.Errorf("ParseRules(%q): got %v, %v, want %v", r.String(), got, err, r)
+		}
+	}
+	for _, s := range []string{"", "3,3", "3x3,3", "3,3,3,", "3,3,4"} {
+		if _, err := ttt.ParseRules(s); err == nil {
+			t.Errorf("ParseRules(%q): got nil error, want non-nil", s)
+		}
+	}
+}
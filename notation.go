@@ -0,0 +1,92 @@
+package tictactoe
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarshalText encodes board b in compact notation: a single line
+// with one character per cell in row major order, 'X' for X, 'O' for O,
+// and '.' for a free cell. For example, "XO.X..O..".
+func (b Board) MarshalText() ([]byte, error) {
+	text := make([]byte, len(b.Cells))
+	for i, cell := range b.Cells {
+		switch cell {
+		case F:
+			text[i] = '.'
+		case X:
+			text[i] = 'X'
+		case O:
+			text[i] = 'O'
+		default:
+			return nil, fmt.Errorf("cell %v has invalid state %d", i, cell)
+		}
+	}
+	return text, nil
+}
+
+// UnmarshalText decodes a board in compact notation. See MarshalText.
+func (b *Board) UnmarshalText(text []byte) error {
+	if len(text) != len(b.Cells) {
+		return fmt.Errorf("board has %v cells, want %v", len(text), len(b.Cells))
+	}
+	var cells [9]State
+	for i, c := range text {
+		switch c {
+		case '.':
+			cells[i] = F
+		case 'X':
+			cells[i] = X
+		case 'O':
+			cells[i] = O
+		default:
+			return fmt.Errorf("cell %v has invalid mark %q", i, c)
+		}
+	}
+	b.Cells = cells
+	return nil
+}
+
+// ParseBoard parses a board in either compact notation (see Board.MarshalText),
+// or the multi-line notation produced by Board.String.
+func ParseBoard(s string) (Board, error) {
+	s = strings.Trim(s, "\n")
+	if !strings.Contains(s, "\n") {
+		var b Board
+		err := b.UnmarshalText([]byte(strings.TrimSpace(s)))
+		return b, err
+	}
+
+	lines := strings.Split(s, "\n")
+	if len(lines) != 5 {
+		return Board{}, fmt.Errorf("board has %v lines, want 5", len(lines))
+	}
+	var b Board
+	for i, line := range lines {
+		if i%2 == 1 {
+			// Separator between rows.
+			if strings.TrimSpace(line) != "───┼───┼───" {
+				return Board{}, fmt.Errorf("line %v: invalid row separator %q", i+1, line)
+			}
+			continue
+		}
+		row := i / 2
+		cells := strings.Split(line, "│")
+		if len(cells) != 3 {
+			return Board{}, fmt.Errorf("line %v: row has %v cells, want 3", i+1, len(cells))
+		}
+		for col, cell := range cells {
+			switch strings.TrimSpace(cell) {
+			case "":
+				b.Cells[3*row+col] = F
+			case "X":
+				b.Cells[3*row+col] = X
+			case "O":
+				b.Cells[3*row+col] = O
+			default:
+				return Board{}, fmt.Errorf("line %v: cell %v has invalid mark %q", i+1, col+1, cell)
+			}
+		}
+	}
+	return b, nil
+}
@@ -0,0 +1,67 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestBoardText(t *testing.T) {
+	b := ttt.Board{
+		Cells: [9]ttt.State{
+			ttt.X, ttt.O, ttt.F,
+			ttt.X, ttt.F, ttt.F,
+			ttt.O, ttt.F, ttt.F,
+		},
+	}
+	text, err := b.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(text), "XO.X..O.."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, s := range []string{"XO.X..O..", "XO.X..O..\n", b.String(), "\n" + b.String() + "\n"} {
+		got, err := ttt.ParseBoard(s)
+		if err != nil {
+			t.Errorf("ParseBoard(%q): %v", s, err)
+			continue
+		}
+		if got != b {
+			t.Errorf("ParseBoard(%q): got\n%v\nwant\n%v", s, got, b)
+		}
+	}
+
+	for _, s := range []string{"", "XO.X..O.", "XO.X..O...", "xo.x..o..", "XO.X..O.-", " X │ O \n───┼───┼───\n"} {
+		if _, err := ttt.ParseBoard(s); err == nil {
+			t.Errorf("ParseBoard(%q): got nil error, want non-nil", s)
+		}
+	}
+}
+
+// TestBoardTextRoundTrip checks that every possible cell configuration
+// survives a round trip through both notations.
+func TestBoardTextRoundTrip(t *testing.T) {
+	for _, b := range allBoards() {
+		text, err := b.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got ttt.Board
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+		if got != b {
+			t.Fatalf("compact notation round trip: got\n%v\nwant\n%v", got, b)
+		}
+
+		got, err = ttt.ParseBoard(b.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != b {
+			t.Fatalf("multi-line notation round trip: got\n%v\nwant\n%v", got, b)
+		}
+	}
+}
@@ -7,30 +7,75 @@ import (
 	"time"
 
 	"github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/registry"
 )
 
+func init() {
+	registry.Register(registry.Entry{
+		Name:        "bad",
+		Description: "Doesn't know how to play, and takes too long to say so.",
+		Options:     []registry.Option{registry.NameOption, registry.InstantOption},
+		New: func(opt registry.Options) (tictactoe.Player, error) {
+			return NewPlayer(Name(opt.Name), Instant(opt.Instant))
+		},
+	})
+}
+
 // NewPlayer creates a bad player.
-func NewPlayer() (tictactoe.Player, error) {
-	return player{}, nil
+func NewPlayer(opts ...Option) (tictactoe.Player, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := player{name: "Bad Player"}
+	if o.name != "" {
+		p.name = o.name
+	}
+	p.instant = o.instant
+	return p, nil
+}
+
+// Option configures a player created by NewPlayer.
+type Option func(*options)
+
+type options struct {
+	name    string
+	instant bool
 }
 
-type player struct{}
+// Name sets the name of the player, if it's not empty.
+func Name(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// Instant makes the player give up right away if instant is true,
+// rather than take lots of time to think about it first.
+func Instant(instant bool) Option {
+	return func(o *options) { o.instant = instant }
+}
+
+type player struct {
+	name    string
+	instant bool // Give up without thinking.
+}
 
 // Name of player.
-func (player) Name() string {
-	return "Bad Player"
+func (p player) Name() string {
+	return p.name
 }
 
 // Play takes a tic-tac-toe board b and returns the next move
 // for this player. Its mark is either X or O.
 // ctx is expected to have a deadline set, and Play may take time
 // to "think" until deadline is reached before returning.
-func (player) Play(ctx context.Context, b tictactoe.Board, mark tictactoe.State) (tictactoe.Move, error) {
+func (p player) Play(ctx context.Context, b tictactoe.Board, mark tictactoe.State) (tictactoe.Move, error) {
 	// Who cares about some deadline?
 	_, _ = ctx.Deadline()
 
 	// Take lots of time to think about what to do...
-	time.Sleep(20 * time.Second)
+	if !p.instant {
+		time.Sleep(20 * time.Second)
+	}
 
 	return 0, fmt.Errorf("... I have no idea how to play tic-tac-toe. :( Can you help me?")
 }
@@ -5,20 +5,64 @@ import (
 	"context"
 
 	"github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/registry"
 )
 
+func init() {
+	registry.Register(registry.Entry{
+		Name:        "human",
+		Description: "Lets a human choose moves by clicking board cells in the browser, or entering them on stdin in the terminal.",
+		Options:     []registry.Option{registry.NameOption},
+		New: func(opt registry.Options) (tictactoe.Player, error) {
+			return NewPlayer(Name(opt.Name))
+		},
+	})
+}
+
 // NewPlayer creates a human-controlled player.
-func NewPlayer() (tictactoe.Player, error) {
-	return player{chosenMove: make(chan tictactoe.Move)}, nil
+func NewPlayer(opts ...Option) (tictactoe.Player, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	p := player{
+		name:    "Human Player",
+		actions: make(chan action, 1),
+		answers: make(chan bool, 1),
+	}
+	if o.name != "" {
+		p.name = o.name
+	}
+	return p, nil
+}
+
+// Option configures a player created by NewPlayer.
+type Option func(*options)
+
+type options struct {
+	name string
+}
+
+// Name sets the name of the player, if it's not empty.
+func Name(name string) Option {
+	return func(o *options) { o.name = name }
 }
 
 type player struct {
-	chosenMove chan tictactoe.Move
+	name    string
+	actions chan action // Holds the human's action on the player's turn until Play takes it.
+	answers chan bool   // Holds the human's answer to a draw offer until AcceptDraw takes it.
+}
+
+// action is a move, or ErrResign or ErrOfferDraw.
+type action struct {
+	move tictactoe.Move
+	err  error
 }
 
 // Name of player.
-func (player) Name() string {
-	return "Human Player"
+func (p player) Name() string {
+	return p.name
 }
 
 // Play takes a tic-tac-toe board b and returns the next move
@@ -26,11 +70,75 @@ func (player) Name() string {
 // ctx is expected to have a deadline set, and Play may take time
 // to "think" until deadline is reached before returning.
 func (p player) Play(ctx context.Context, b tictactoe.Board, mark tictactoe.State) (tictactoe.Move, error) {
+	return p.move(ctx)
+}
+
+// PlayMNK is like Play, but on an m,n,k-game board of any size.
+func (p player) PlayMNK(ctx context.Context, b tictactoe.MNKBoard, mark tictactoe.State) (tictactoe.Move, error) {
+	return p.move(ctx)
+}
+
+// move waits for the human's action on the player's turn.
+func (p player) move(ctx context.Context) (tictactoe.Move, error) {
 	// Outsource our decision-making process to the human.
 	// They know what they're doing. Hopefully.
-	return <-p.chosenMove, nil
+	select {
+	case a := <-p.actions:
+		return a.move, a.err
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
 }
 
+// AcceptDraw waits for the human to accept or decline a draw offer.
+func (p player) AcceptDraw(ctx context.Context, b tictactoe.Board, mark tictactoe.State) bool {
+	select {
+	case accept := <-p.answers:
+		return accept
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// CellClick makes the move in the clicked cell.
 func (p player) CellClick(index int) {
-	p.chosenMove <- tictactoe.Move(index)
+	p.act(action{move: tictactoe.Move(index)})
+}
+
+// Command carries out command c: Resign and OfferDraw on the player's turn,
+// and AcceptDraw and DeclineDraw when the player has been offered a draw.
+func (p player) Command(c tictactoe.Command) {
+	switch c {
+	case tictactoe.Resign:
+		p.act(action{err: tictactoe.ErrResign})
+	case tictactoe.OfferDraw:
+		p.act(action{err: tictactoe.ErrOfferDraw})
+	case tictactoe.AcceptDraw, tictactoe.DeclineDraw:
+		select {
+		case p.answers <- c == tictactoe.AcceptDraw:
+		default:
+			// Already answered.
+		}
+	}
+}
+
+// act passes action a on to Play, unless there's already an action it hasn't taken.
+func (p player) act(a action) {
+	select {
+	case p.actions <- a:
+	default:
+	}
+}
+
+// ObserveMove discards actions and answers that weren't taken
+// by the time a move was made, so they don't carry over to later turns.
+func (p player) ObserveMove(b tictactoe.Board, mark tictactoe.State, move tictactoe.Move) {
+	select {
+	case <-p.actions:
+	default:
+	}
+	select {
+	case <-p.answers:
+	default:
+	}
 }
@@ -2,7 +2,8 @@
 //
 // It always wins if the opponent makes a suboptimal move
 // that opens up an opportunity to guarantee a win.
-// It never loses.
+// It never loses, unless it's made to play at a lower difficulty.
+// It only plays classic tic-tac-toe, not other m,n,k-games.
 package perfect
 
 import (
@@ -10,29 +11,139 @@ import (
 	"fmt"
 	"html/template"
 	"math/rand"
+	"sync"
 	"time"
 
 	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/registry"
 )
 
+func init() {
+	registry.Register(registry.Entry{
+		Name:        "perfect",
+		Description: "Plays perfectly and never loses, unless its difficulty is lowered.",
+		Options:     []registry.Option{registry.SeedOption, registry.DifficultyOption, registry.NameOption, registry.ImageOption, registry.InstantOption},
+		New: func(opt registry.Options) (ttt.Player, error) {
+			opts := []Option{Name(opt.Name), Image(opt.Image), Instant(opt.Instant)}
+			if opt.Seed != nil {
+				opts = append(opts, Seed(*opt.Seed))
+			}
+			if opt.Difficulty != 0 {
+				opts = append(opts, Difficulty(opt.Difficulty))
+			}
+			return NewPlayer(opts...)
+		},
+	})
+}
+
 // NewPlayer creates a perfect player.
-func NewPlayer() (ttt.Player, error) {
-	return player{
-		rand: rand.New(rand.NewSource(time.Now().UnixNano())),
-	}, nil
+// By default, it's seeded from the current time,
+// which it uses to choose between equally good moves,
+// and it plays at registry.Hard difficulty.
+func NewPlayer(opts ...Option) (ttt.Player, error) {
+	o := options{seed: time.Now().UnixNano(), seeded: true, difficulty: registry.Hard}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.source == nil {
+		o.source = rand.NewSource(o.seed)
+	}
+	p := player{
+		rand:  rand.New(o.source),
+		name:  "Perfect Player",
+		image: "https://raw.githubusercontent.com/shurcooL/tictactoe/master/player/perfect/gopher-fancy.png",
+	}
+	switch o.difficulty {
+	case registry.Easy:
+		p.mistakes = 0.5
+	case registry.Medium:
+		p.mistakes = 0.2
+	case registry.Hard:
+		p.mistakes = 0
+	default:
+		return nil, fmt.Errorf("difficulty %d is not valid", o.difficulty)
+	}
+	if o.name != "" {
+		p.name = o.name
+	}
+	if o.image != "" {
+		p.image = o.image
+	}
+	p.instant = o.instant
+	p.seed, p.seeded = o.seed, o.seeded
+	return p, nil
+}
+
+// Option configures a player created by NewPlayer.
+type Option func(*options)
+
+type options struct {
+	seed       int64
+	seeded     bool // Whether seed is the seed of source.
+	source     rand.Source
+	difficulty registry.Difficulty
+	name       string
+	image      template.URL
+	instant    bool
+}
+
+// Seed makes the player use the given seed to choose between
+// equally good moves, so that it plays the same moves in the same positions.
+func Seed(seed int64) Option {
+	return func(o *options) { o.seed, o.seeded, o.source = seed, true, nil }
+}
+
+// Source makes the player use the given source to choose between
+// equally good moves. The player's seed is then unknown,
+// so its games can only be reproduced by the caller.
+func Source(source rand.Source) Option {
+	return func(o *options) { o.seeded, o.source = false, source }
+}
+
+// Difficulty sets how well the player plays. At registry.Hard difficulty
+// it plays perfectly, and at lower difficulties it sometimes plays
+// a random legal move instead of the best one.
+func Difficulty(d registry.Difficulty) Option {
+	return func(o *options) { o.difficulty = d }
+}
+
+// Name sets the name of the player, if it's not empty.
+func Name(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// Instant makes the player move as soon as it decides on a move if instant is true,
+// rather than take some more time to pretend it's still thinking.
+func Instant(instant bool) Option {
+	return func(o *options) { o.instant = instant }
+}
+
+// Image sets the image of the player, if it's not empty.
+func Image(image template.URL) Option {
+	return func(o *options) { o.image = image }
 }
 
 type player struct {
-	rand *rand.Rand
+	rand     *rand.Rand
+	mistakes float64 // Probability of playing a random move instead of the best one.
+	name     string
+	image    template.URL
+	instant  bool // Don't pretend to think.
+	seed     int64
+	seeded   bool // Whether seed is known.
 }
 
 // Name of player.
-func (player) Name() string {
-	return "Perfect Player"
+func (p player) Name() string {
+	return p.name
 }
 
-func (player) Image() template.URL {
-	return "https://raw.githubusercontent.com/shurcooL/tictactoe/master/player/perfect/gopher-fancy.png"
+func (p player) Image() template.URL {
+	return p.image
+}
+
+func (p player) Seed() (seed int64, ok bool) {
+	return p.seed, p.seeded
 }
 
 // Play takes a tic-tac-toe board b and returns the next move
@@ -40,6 +151,9 @@ func (player) Image() template.URL {
 // ctx is expected to have a deadline set, and Play may take time
 // to "think" until deadline is reached before returning.
 func (p player) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	if err := checkTurn(b, mark); err != nil {
+		return ttt.Move(-1), err
+	}
 	if b.Condition() != ttt.NotEnd {
 		return ttt.Move(-1), fmt.Errorf("board has a finished game")
 	}
@@ -65,12 +179,45 @@ func (p player) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move
 	}
 	move := strongMoves[p.rand.Intn(len(strongMoves))]
 
+	// Make a mistake every now and then, if we're told to.
+	if p.mistakes > 0 && p.rand.Float64() < p.mistakes {
+		legalMoves := b.LegalMoves()
+		move = legalMoves[p.rand.Intn(len(legalMoves))]
+	}
+
 	// Take some more time to pretend we're still "thinking".
-	time.Sleep(time.Until(stopThinking))
+	if !p.instant {
+		time.Sleep(time.Until(stopThinking))
+	}
 
 	return move, nil
 }
 
+// AcceptDraw accepts a draw offer unless it can guarantee a win.
+func (player) AcceptDraw(ctx context.Context, b ttt.Board, mark ttt.State) bool {
+	if (mark != ttt.X && mark != ttt.O) || checkTurn(b, mark.Opponent()) != nil || b.Condition() != ttt.NotEnd {
+		return false
+	}
+	// It's the opponent's turn. If the strongest guarantee
+	// the opponent can ensure is a loss, we can guarantee a win.
+	opponentMoves := evaluateBoard(b, mark.Opponent())
+	return strongest(opponentMoves).Guarantee != guaranteeLoss
+}
+
+// checkTurn returns an error if board b isn't a position
+// that can be reached in legal play with mark to move next.
+func checkTurn(b ttt.Board, mark ttt.State) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	for _, first := range []ttt.State{ttt.X, ttt.O} {
+		if next, err := b.NextMark(first); err == nil && next == mark {
+			return nil
+		}
+	}
+	return fmt.Errorf("it's not %v's turn", mark)
+}
+
 type guarantee uint8
 
 const (
@@ -84,8 +231,26 @@ type evaluatedMove struct {
 	Guarantee guarantee
 }
 
+// evaluations caches the results of evaluateBoard, which only depend on its arguments.
+// It maps position keys to []evaluatedMove values, which must not be modified.
+var evaluations sync.Map
+
+type position struct {
+	b    ttt.Board
+	mark ttt.State
+}
+
 func evaluateBoard(b ttt.Board, mark ttt.State) []evaluatedMove {
-	legalMoves := legalMoves(b)
+	if moves, ok := evaluations.Load(position{b, mark}); ok {
+		return moves.([]evaluatedMove)
+	}
+	moves := evaluateBoardUncached(b, mark)
+	evaluations.Store(position{b, mark}, moves)
+	return moves
+}
+
+func evaluateBoardUncached(b ttt.Board, mark ttt.State) []evaluatedMove {
+	legalMoves := b.LegalMoves()
 
 	// Fast path for empty board.
 	if len(legalMoves) == len(b.Cells) {
@@ -130,10 +295,14 @@ func evaluateMove(move ttt.Move, b ttt.Board, mark ttt.State) guarantee {
 		}
 	case ttt.Tie:
 		return guaranteeTie
+	case ttt.Invalid:
+		// The move leads to a position that can't be reached in legal play,
+		// so it's not a move we can make.
+		return guaranteeLoss
 	case ttt.NotEnd:
 		// See what would happen if the opponent plays perfectly
 		// and makes a follow-up move with the strongest guarantee.
-		opponent := opponentOf(mark)
+		opponent := mark.Opponent()
 		opponentMoves := evaluateBoard(b, opponent)
 		strongestOpponentMove := strongest(opponentMoves).Move
 		switch evaluateMove(strongestOpponentMove, b, opponent) {
@@ -157,18 +326,6 @@ func evaluateMove(move ttt.Move, b ttt.Board, mark ttt.State) guarantee {
 	}
 }
 
-// legalMoves returns all legal moves on board b.
-func legalMoves(b ttt.Board) []ttt.Move {
-	var moves []ttt.Move
-	for i, cell := range b.Cells {
-		if cell != ttt.F {
-			continue
-		}
-		moves = append(moves, ttt.Move(i))
-	}
-	return moves
-}
-
 // strongest returns a move with the strongest guarantee.
 // moves must contain at least 1 element.
 func strongest(moves []evaluatedMove) evaluatedMove {
@@ -180,14 +337,3 @@ func strongest(moves []evaluatedMove) evaluatedMove {
 	}
 	return strongest
 }
-
-func opponentOf(mark ttt.State) ttt.State {
-	switch mark {
-	case ttt.X:
-		return ttt.O
-	case ttt.O:
-		return ttt.X
-	default:
-		panic("unreachable")
-	}
-}
@@ -0,0 +1,28 @@
+package perfect_test
+
+import (
+	"context"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/player/perfect"
+)
+
+func TestWrongTurn(t *testing.T) {
+	player, err := perfect.NewPlayer(perfect.Instant(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// X has moved, so it's O's turn whoever moved first.
+	b := ttt.Board{Cells: [9]ttt.State{ttt.X}}
+
+	if _, err := player.Play(context.Background(), b, ttt.X); err == nil {
+		t.Error("X played on O's turn, want error")
+	}
+	if _, err := player.Play(context.Background(), b, ttt.O); err != nil {
+		t.Errorf("O couldn't play on its turn: %v", err)
+	}
+	if player.(ttt.DrawAccepter).AcceptDraw(context.Background(), b, ttt.O) {
+		t.Error("O accepted a draw offered by itself")
+	}
+}
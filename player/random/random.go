@@ -9,41 +9,133 @@ import (
 	"time"
 
 	"github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/registry"
 )
 
+func init() {
+	registry.Register(registry.Entry{
+		Name:        "random",
+		Description: "Plays a random legal move.",
+		Options:     []registry.Option{registry.SeedOption, registry.NameOption, registry.ImageOption, registry.InstantOption},
+		New: func(opt registry.Options) (tictactoe.Player, error) {
+			opts := []Option{Name(opt.Name), Image(opt.Image), Instant(opt.Instant)}
+			if opt.Seed != nil {
+				opts = append(opts, Seed(*opt.Seed))
+			}
+			return NewPlayer(opts...)
+		},
+	})
+}
+
 // NewPlayer creates a random player of tic-tac-toe.
-func NewPlayer() (tictactoe.Player, error) {
+// By default, it's seeded from the current time.
+func NewPlayer(opts ...Option) (tictactoe.Player, error) {
 	gophers := []template.URL{
 		"https://raw.githubusercontent.com/shurcooL/tictactoe/master/player/random/gopher-0.png",
 		"https://raw.githubusercontent.com/shurcooL/tictactoe/master/player/random/gopher-1.png",
 		"https://raw.githubusercontent.com/shurcooL/tictactoe/master/player/random/gopher-2.png",
 	}
-	rand := rand.New(rand.NewSource(time.Now().UnixNano()))
-	return player{
+	o := options{seed: time.Now().UnixNano(), seeded: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.source == nil {
+		o.source = rand.NewSource(o.seed)
+	}
+	rand := rand.New(o.source)
+	p := player{
 		rand:  rand,
+		name:  "Random Player",
 		image: gophers[rand.Intn(len(gophers))],
-	}, nil
+	}
+	if o.name != "" {
+		p.name = o.name
+	}
+	if o.image != "" {
+		p.image = o.image
+	}
+	p.instant = o.instant
+	p.seed, p.seeded = o.seed, o.seeded
+	return p, nil
+}
+
+// Option configures a player created by NewPlayer.
+type Option func(*options)
+
+type options struct {
+	seed    int64
+	seeded  bool // Whether seed is the seed of source.
+	source  rand.Source
+	name    string
+	image   template.URL
+	instant bool
+}
+
+// Seed makes the player use the given seed for its randomness,
+// so that it plays the same moves in the same positions.
+func Seed(seed int64) Option {
+	return func(o *options) { o.seed, o.seeded, o.source = seed, true, nil }
+}
+
+// Source makes the player use the given source for its randomness.
+// The player's seed is then unknown, so its games can only be
+// reproduced by the caller.
+func Source(source rand.Source) Option {
+	return func(o *options) { o.seeded, o.source = false, source }
+}
+
+// Name sets the name of the player, if it's not empty.
+func Name(name string) Option {
+	return func(o *options) { o.name = name }
+}
+
+// Instant makes the player move as soon as it decides on a move if instant is true,
+// rather than take some more time to pretend it's still thinking.
+func Instant(instant bool) Option {
+	return func(o *options) { o.instant = instant }
+}
+
+// Image sets the image of the player, if it's not empty.
+// By default, it's one of a few gophers, chosen at random.
+func Image(image template.URL) Option {
+	return func(o *options) { o.image = image }
 }
 
 // player is a random player of tic-tac-toe.
 type player struct {
-	rand  *rand.Rand
-	image template.URL
+	rand    *rand.Rand
+	name    string
+	image   template.URL
+	instant bool // Don't pretend to think.
+	seed    int64
+	seeded  bool // Whether seed is known.
 }
 
 func (p player) Name() string {
-	return "Random Player"
+	return p.name
 }
 
 func (p player) Image() template.URL {
 	return p.image
 }
 
+func (p player) Seed() (seed int64, ok bool) {
+	return p.seed, p.seeded
+}
+
 // Play takes a tic-tac-toe board b and returns the next move
 // for this player. Its mark is either X or O.
 // ctx is expected to have a deadline set, and Play may take time
 // to "think" until deadline is reached before returning.
 func (p player) Play(ctx context.Context, b tictactoe.Board, mark tictactoe.State) (tictactoe.Move, error) {
+	return p.PlayMNK(ctx, b.MNK(), mark)
+}
+
+// PlayMNK is like Play, but on an m,n,k-game board of any size.
+func (p player) PlayMNK(ctx context.Context, b tictactoe.MNKBoard, mark tictactoe.State) (tictactoe.Move, error) {
+	if err := b.Validate(); err != nil {
+		return tictactoe.Move(-1), err
+	}
 	if b.Condition() != tictactoe.NotEnd {
 		return tictactoe.Move(-1), fmt.Errorf("board has a finished game")
 	}
@@ -57,23 +149,13 @@ func (p player) Play(ctx context.Context, b tictactoe.Board, mark tictactoe.Stat
 	}
 
 	// Decide on a move to make... using randomness!
-	legalMoves := legalMoves(b)
+	legalMoves := b.LegalMoves()
 	move := legalMoves[p.rand.Intn(len(legalMoves))]
 
 	// Take some more time to pretend we're still "thinking".
-	time.Sleep(time.Until(stopThinking))
+	if !p.instant {
+		time.Sleep(time.Until(stopThinking))
+	}
 
 	return move, nil
 }
-
-// legalMoves returns all legal moves on board b.
-func legalMoves(b tictactoe.Board) []tictactoe.Move {
-	var moves []tictactoe.Move
-	for i, cell := range b.Cells {
-		if cell != tictactoe.F {
-			continue
-		}
-		moves = append(moves, tictactoe.Move(i))
-	}
-	return moves
-}
@@ -2,6 +2,8 @@ package random_test
 
 import (
 	"context"
+	"fmt"
+	"math/rand"
 	"testing"
 	"time"
 
@@ -35,3 +37,44 @@ func Test(t *testing.T) {
 		t.Errorf("not the expected move: %v", move)
 	}
 }
+
+func TestSeed(t *testing.T) {
+	play := func() []ttt.Move {
+		player, err := random.NewPlayer(random.Seed(42))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var moves []ttt.Move
+		for i := 0; i < 10; i++ {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+			move, err := player.Play(ctx, ttt.Board{}, ttt.X)
+			cancel()
+			if err != nil {
+				t.Fatal(err)
+			}
+			moves = append(moves, move)
+		}
+		return moves
+	}
+	if a, b := play(), play(); fmt.Sprint(a) != fmt.Sprint(b) {
+		t.Errorf("players with the same seed played different moves: %v and %v", a, b)
+	}
+}
+
+func TestSeeder(t *testing.T) {
+	player, err := random.NewPlayer(random.Seed(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seed, ok := player.(ttt.Seeder).Seed(); !ok || seed != 42 {
+		t.Errorf("got seed %v, %v; want 42, true", seed, ok)
+	}
+
+	player, err = random.NewPlayer(random.Source(rand.NewSource(42)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := player.(ttt.Seeder).Seed(); ok {
+		t.Error("got a known seed for a player with a custom source")
+	}
+}
@@ -0,0 +1,30 @@
+package rating
+
+import "math"
+
+// InitialElo is the Elo rating of a new player.
+const InitialElo = 1500
+
+// Elo is the Elo rating system.
+type Elo struct {
+	// K is the maximum change in rating from a single game.
+	// Zero means 32.
+	K float64
+}
+
+// Expected returns the expected score of a player rated a
+// against a player rated b, between 0 and 1.
+func (Elo) Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Update returns the new ratings of players rated a and b
+// after a game where a scored score: 1 for a win, ½ for a draw, 0 for a loss.
+func (e Elo) Update(a, b, score float64) (newA, newB float64) {
+	k := e.K
+	if k == 0 {
+		k = 32
+	}
+	change := k * (score - e.Expected(a, b))
+	return a + change, b - change
+}
@@ -0,0 +1,103 @@
+package rating
+
+import "math"
+
+// Glicko is a Glicko-2 rating, on the Glicko scale.
+type Glicko struct {
+	Rating     float64 `json:"rating"`
+	RD         float64 `json:"rd"`         // Rating deviation. The player's rating is within about 2 RD of Rating with 95% confidence.
+	Volatility float64 `json:"volatility"` // Degree of expected fluctuation in the player's rating.
+}
+
+// InitialGlicko is the Glicko-2 rating of a new player.
+var InitialGlicko = Glicko{Rating: 1500, RD: 350, Volatility: 0.06}
+
+// glickoScale converts between the Glicko scale and the Glicko-2 scale.
+const glickoScale = 173.7178
+
+// Glicko2 is the Glicko-2 rating system.
+//
+// Ratings are updated once per rating period, using all games
+// each player played during it. See http://www.glicko.net/glicko/glicko2.pdf.
+type Glicko2 struct {
+	// Tau constrains the change in volatility over time.
+	// Smaller values, between 0.3 and 1.2, prevent large changes.
+	// Zero means 0.5.
+	Tau float64
+}
+
+// Opponent faced by a player during a rating period.
+type Opponent struct {
+	Glicko
+	Score float64 // Player's score against the opponent: 1 for a win, ½ for a draw, 0 for a loss.
+}
+
+// Update returns the new rating of player p after a rating period
+// in which it faced opponents. If p had no games, only its RD grows.
+func (s Glicko2) Update(p Glicko, opponents []Opponent) Glicko {
+	tau := s.Tau
+	if tau == 0 {
+		tau = 0.5
+	}
+
+	// Step 2: convert to the Glicko-2 scale.
+	mu, phi, sigma := (p.Rating-1500)/glickoScale, p.RD/glickoScale, p.Volatility
+	if len(opponents) == 0 {
+		phi = math.Sqrt(phi*phi + sigma*sigma)
+		return Glicko{Rating: p.Rating, RD: phi * glickoScale, Volatility: sigma}
+	}
+
+	// Steps 3 and 4: estimated variance and improvement.
+	var vInv, sum float64
+	for _, o := range opponents {
+		muJ, phiJ := (o.Rating-1500)/glickoScale, o.RD/glickoScale
+		g := g(phiJ)
+		e := 1 / (1 + math.Exp(-g*(mu-muJ)))
+		vInv += g * g * e * (1 - e)
+		sum += g * (o.Score - e)
+	}
+	v := 1 / vInv
+	delta := v * sum
+
+	// Step 5: new volatility, by the Illinois algorithm.
+	const epsilon = 0.000001
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		return ex*(delta*delta-phi*phi-v-ex)/(2*math.Pow(phi*phi+v+ex, 2)) - (x-a)/(tau*tau)
+	}
+	A, B := a, 0.0
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*tau) < 0 {
+			k++
+		}
+		B = a - k*tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > epsilon {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	sigma = math.Exp(A / 2)
+
+	// Steps 6 and 7: new rating deviation and rating.
+	phiStar := math.Sqrt(phi*phi + sigma*sigma)
+	phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	mu += phi * phi * sum
+
+	// Step 8: convert back to the Glicko scale.
+	return Glicko{Rating: mu*glickoScale + 1500, RD: phi * glickoScale, Volatility: sigma}
+}
+
+func g(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
@@ -0,0 +1,186 @@
+// Package rating rates the strength of tic-tac-toe players
+// with the Elo and Glicko-2 rating systems.
+//
+// Ratings are kept per player name in a Table, which is updated
+// with the results of games and can be saved to and loaded from a file,
+// so that a player's strength can be tracked over time:
+//
+//	t, err := rating.Load("ratings.json")
+//	t.Update([]rating.Game{rating.NewGame("random", "perfect", result)})
+//	err = t.Save("ratings.json")
+package rating
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/referee"
+)
+
+// Player's ratings.
+type Player struct {
+	Elo     float64 `json:"elo"`
+	Glicko2 Glicko  `json:"glicko2"`
+	Games   int     `json:"games"` // Number of rated games played.
+}
+
+// Game between two named players, for rating purposes.
+type Game struct {
+	X, O    string        // Names of the players of X and O.
+	Outcome ttt.Condition // XWon, OWon or Tie. Games with other outcomes aren't rated.
+}
+
+// NewGame returns the game between players named x and o
+// that ended with result r.
+func NewGame(x, o string, r referee.Result) Game {
+	return Game{X: x, O: o, Outcome: r.Outcome()}
+}
+
+// score returns X's score: 1 for a win, ½ for a draw, 0 for a loss.
+// ok is false if the game isn't rated.
+func (g Game) score() (score float64, ok bool) {
+	switch g.Outcome {
+	case ttt.XWon:
+		return 1, true
+	case ttt.OWon:
+		return 0, true
+	case ttt.Tie:
+		return 0.5, true
+	default:
+		return 0, false
+	}
+}
+
+// Table of players' ratings, by player name.
+// The zero value is an empty table that uses default rating systems.
+type Table struct {
+	Players map[string]Player
+
+	Elo     Elo     // Elo rating system used to update Elo ratings.
+	Glicko2 Glicko2 // Glicko-2 rating system used to update Glicko-2 ratings.
+}
+
+// Player returns the ratings of the named player.
+// Players that haven't played a rated game have initial ratings.
+func (t *Table) Player(name string) Player {
+	if p, ok := t.Players[name]; ok {
+		return p
+	}
+	return Player{Elo: InitialElo, Glicko2: InitialGlicko}
+}
+
+// Update updates the ratings with games played during a rating period.
+//
+// Elo ratings are updated after each game, in order. Glicko-2 ratings
+// are updated once, using all games each player played in the period,
+// and the rating deviation of players in the table who didn't play grows.
+// To rate games one at a time, call Update with a single game.
+func (t *Table) Update(games []Game) {
+	if t.Players == nil {
+		t.Players = make(map[string]Player)
+	}
+	updated := make(map[string]Player)
+	for name, p := range t.Players {
+		updated[name] = p
+	}
+
+	opponents := make(map[string][]Opponent)
+	for _, g := range games {
+		score, ok := g.score()
+		if !ok {
+			continue
+		}
+		x, o := t.Player(g.X), t.Player(g.O)
+		opponents[g.X] = append(opponents[g.X], Opponent{Glicko: o.Glicko2, Score: score})
+		opponents[g.O] = append(opponents[g.O], Opponent{Glicko: x.Glicko2, Score: 1 - score})
+
+		ux, uo := t.updatedPlayer(updated, g.X), t.updatedPlayer(updated, g.O)
+		ux.Elo, uo.Elo = t.Elo.Update(ux.Elo, uo.Elo, score)
+		ux.Games++
+		uo.Games++
+		updated[g.X], updated[g.O] = ux, uo
+	}
+
+	for name, p := range updated {
+		p.Glicko2 = t.Glicko2.Update(t.Player(name).Glicko2, opponents[name])
+		t.Players[name] = p
+	}
+}
+
+// updatedPlayer returns the named player from updated,
+// or its ratings from t if it's not there yet.
+func (t *Table) updatedPlayer(updated map[string]Player, name string) Player {
+	if p, ok := updated[name]; ok {
+		return p
+	}
+	return t.Player(name)
+}
+
+// Names returns the names of all rated players,
+// from the highest Glicko-2 rating to the lowest.
+func (t *Table) Names() []string {
+	var names []string
+	for name := range t.Players {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := t.Players[names[i]].Glicko2.Rating, t.Players[names[j]].Glicko2.Rating
+		if a != b {
+			return a > b
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// WriteText writes the ratings of all players as an aligned text table,
+// from the highest Glicko-2 rating to the lowest.
+func (t *Table) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Player\tGames\tElo\tGlicko-2\tRD\tVolatility")
+	for _, name := range t.Names() {
+		p := t.Players[name]
+		fmt.Fprintf(tw, "%v\t%v\t%.0f\t%.0f\t%.0f\t%.4f\n", name, p.Games, p.Elo, p.Glicko2.Rating, p.Glicko2.RD, p.Glicko2.Volatility)
+	}
+	return tw.Flush()
+}
+
+// Load loads a table of ratings from the named file.
+// If the file doesn't exist, it returns an empty table.
+func Load(name string) (*Table, error) {
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return &Table{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var players map[string]Player
+	err = json.NewDecoder(f).Decode(&players)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ratings: %v", err)
+	}
+	return &Table{Players: players}, nil
+}
+
+// Save saves the table's ratings to the named file,
+// as a JSON object of players' ratings by name.
+func (t *Table) Save(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "\t")
+	err = enc.Encode(t.Players)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to save ratings: %v", err)
+	}
+	return f.Close()
+}
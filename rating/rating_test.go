@@ -0,0 +1,91 @@
+package rating_test
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/rating"
+)
+
+func TestElo(t *testing.T) {
+	var elo rating.Elo
+	if got := elo.Expected(1500, 1500); got != 0.5 {
+		t.Errorf("got expected score %v between equal ratings, want 0.5", got)
+	}
+	a, b := elo.Update(1500, 1500, 1)
+	if a != 1516 || b != 1484 {
+		t.Errorf("got ratings %v and %v after a win, want 1516 and 1484", a, b)
+	}
+	a, b = elo.Update(1600, 1400, 0.5)
+	if a >= 1600 || b <= 1400 || a+b != 3000 {
+		t.Errorf("got ratings %v and %v after a draw, want the stronger player to lose points to the weaker", a, b)
+	}
+}
+
+// TestGlicko2 tests the example from http://www.glicko.net/glicko/glicko2.pdf.
+func TestGlicko2(t *testing.T) {
+	got := rating.Glicko2{Tau: 0.5}.Update(rating.Glicko{Rating: 1500, RD: 200, Volatility: 0.06}, []rating.Opponent{
+		{Glicko: rating.Glicko{Rating: 1400, RD: 30}, Score: 1},
+		{Glicko: rating.Glicko{Rating: 1550, RD: 100}, Score: 0},
+		{Glicko: rating.Glicko{Rating: 1700, RD: 300}, Score: 0},
+	})
+	want := rating.Glicko{Rating: 1464.06, RD: 151.52, Volatility: 0.05999}
+	if math.Abs(got.Rating-want.Rating) > 0.01 || math.Abs(got.RD-want.RD) > 0.01 || math.Abs(got.Volatility-want.Volatility) > 0.00001 {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// A player that didn't play becomes less certain.
+	idle := rating.Glicko2{}.Update(rating.InitialGlicko, nil)
+	if idle.Rating != rating.InitialGlicko.Rating || idle.RD <= rating.InitialGlicko.RD {
+		t.Errorf("got %+v for an idle player, want same rating with a larger RD than %+v", idle, rating.InitialGlicko)
+	}
+}
+
+func TestTable(t *testing.T) {
+	var table rating.Table
+	table.Update([]rating.Game{
+		{X: "perfect", O: "random", Outcome: ttt.XWon},
+		{X: "random", O: "perfect", Outcome: ttt.OWon},
+		{X: "random", O: "perfect", Outcome: ttt.Tie},
+		{X: "random", O: "perfect", Outcome: ttt.NotEnd}, // Not rated.
+	})
+	perfect, random := table.Player("perfect"), table.Player("random")
+	if perfect.Games != 3 || random.Games != 3 {
+		t.Errorf("got %v and %v games, want 3 each", perfect.Games, random.Games)
+	}
+	if perfect.Elo <= random.Elo || perfect.Glicko2.Rating <= random.Glicko2.Rating {
+		t.Errorf("got perfect %+v and random %+v, want perfect rated higher", perfect, random)
+	}
+	if got := table.Names(); len(got) != 2 || got[0] != "perfect" {
+		t.Errorf("got names %v, want perfect first", got)
+	}
+
+	dir, err := ioutil.TempDir("", "rating")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "ratings.json")
+	if err := table.Save(name); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := rating.Load(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Player("perfect"); got != perfect {
+		t.Errorf("got loaded %+v, want %+v", got, perfect)
+	}
+
+	empty, err := rating.Load(filepath.Join(dir, "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := empty.Player("anyone"); got.Elo != rating.InitialElo || got.Glicko2 != rating.InitialGlicko {
+		t.Errorf("got %+v for a new player, want initial ratings", got)
+	}
+}
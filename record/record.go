@@ -0,0 +1,251 @@
+// Package record defines a portable format for records of tic-tac-toe games.
+//
+// A record is a JSON document that holds everything needed to archive,
+// share, replay and verify a game: the players, who moved first,
+// every move with its timestamp and think time, and how the game ended.
+// Records of m,n,k-games with other than classic rules also hold the rules,
+// e.g., "rules": {"width": 4, "height": 4, "k": 4}.
+// For example:
+//
+//	{
+//		"version": 1,
+//		"x": {"name": "Random Player", "seed": 42},
+//		"o": {"name": "Perfect Player"},
+//		"first": "X",
+//		"start": "2019-06-20T20:00:00Z",
+//		"moves": [
+//			{"mark": "X", "move": 4, "time": "2019-06-20T20:00:02Z", "think_ns": 2000000000},
+//			...
+//		],
+//		"result": "o_won",
+//		"termination": "line"
+//	}
+package record
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+// Version of the record format.
+const Version = 1
+
+// Record of a game of tic-tac-toe.
+type Record struct {
+	Version     int           `json:"version"`         // Version of the record format. Write sets it to Version.
+	X           Player        `json:"x"`               // Player with mark X.
+	O           Player        `json:"o"`               // Player with mark O.
+	Rules       *ttt.Rules    `json:"rules,omitempty"` // Rules of the game, if they're not ttt.Classic.
+	First       ttt.State     `json:"first"`           // Mark of the player that moved first.
+	Start       time.Time     `json:"start"`           // Time when the game started.
+	Moves       []Move        `json:"moves"`           // Moves in the order they were made.
+	Result      ttt.Condition `json:"result"`          // Result of the game. It's NotEnd only if the game was aborted.
+	Termination Termination   `json:"termination"`     // How the game ended.
+}
+
+// Player in a game record.
+type Player struct {
+	Name string `json:"name"`
+	Seed *int64 `json:"seed,omitempty"` // Seed of the player's random number generator, if known.
+}
+
+// Move in a game record.
+type Move struct {
+	Mark  ttt.State     `json:"mark"`     // Mark is either X or O.
+	Move  ttt.Move      `json:"move"`     // Move that was made.
+	Time  time.Time     `json:"time"`     // Time when the move was made.
+	Think time.Duration `json:"think_ns"` // Time the player took to make the move.
+}
+
+// Termination is how a game ended.
+type Termination uint8
+
+// Terminations of a game.
+const (
+	Line        Termination = iota + 1 // A player completed a line.
+	BoardFull                          // The board filled up without either player completing a line.
+	Resignation                        // A player resigned.
+	Agreement                          // The players agreed to a draw.
+	Timeout                            // A player took more than the allotted time to move.
+	IllegalMove                        // A player made a move that isn't valid or isn't legal.
+	Panic                              // A player panicked.
+	PlayerError                        // A player failed to make a move.
+	Aborted                            // The game was stopped before it ended.
+)
+
+// terminationNames are the names of terminations, used in JSON.
+var terminationNames = map[Termination]string{
+	Line:        "line",
+	BoardFull:   "board_full",
+	Resignation: "resignation",
+	Agreement:   "agreement",
+	Timeout:     "timeout",
+	IllegalMove: "illegal_move",
+	Panic:       "panic",
+	PlayerError: "error",
+	Aborted:     "aborted",
+}
+
+func (t Termination) String() string {
+	name, ok := terminationNames[t]
+	if !ok {
+		return fmt.Sprintf("Termination(%d)", t)
+	}
+	return name
+}
+
+// Forfeit reports whether termination t is a loss for the player
+// at fault, rather than an outcome of play on the board.
+func (t Termination) Forfeit() bool {
+	switch t {
+	case Timeout, IllegalMove, Panic, PlayerError:
+		return true
+	default:
+		return false
+	}
+}
+
+// MarshalJSON encodes the termination as one of its names,
+// like "line" or "board_full".
+func (t Termination) MarshalJSON() ([]byte, error) {
+	name, ok := terminationNames[t]
+	if !ok {
+		return nil, fmt.Errorf("invalid termination %d", t)
+	}
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON decodes a termination encoded by MarshalJSON.
+func (t *Termination) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("invalid termination %s", data)
+	}
+	for term, n := range terminationNames {
+		if n == name {
+			*t = term
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid termination %q", name)
+}
+
+// Write writes game record r to w.
+// The record is verified first, and nothing is written if it's not valid.
+func Write(w io.Writer, r Record) error {
+	r.Version = Version
+	if err := r.Verify(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(r)
+}
+
+// Read reads a game record from r. It's strict: the record must be
+// of a known version, have no unknown fields or trailing data,
+// and pass verification.
+func Read(r io.Reader) (Record, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var rec Record
+	if err := dec.Decode(&rec); err != nil {
+		return Record{}, fmt.Errorf("invalid record: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return Record{}, fmt.Errorf("invalid record: unexpected data after record")
+	}
+	if rec.Version != Version {
+		return Record{}, fmt.Errorf("invalid record: unsupported version %v", rec.Version)
+	}
+	if err := rec.Verify(); err != nil {
+		return Record{}, err
+	}
+	return rec, nil
+}
+
+// Verify verifies that record r is consistent: the moves are legal
+// and made in turn, timestamps are in order, and the result and
+// termination match how the game was played.
+func (r Record) Verify() error {
+	g, err := r.Game()
+	if err != nil {
+		return err
+	}
+	last := r.Start
+	for i, m := range r.Moves {
+		if m.Time.Before(last) {
+			return fmt.Errorf("invalid record: move %v was made before the previous one", i+1)
+		}
+		if m.Think < 0 {
+			return fmt.Errorf("invalid record: move %v has negative think time", i+1)
+		}
+		last = m.Time
+	}
+
+	board := g.Condition()
+	switch r.Termination {
+	case Line:
+		if board != ttt.XWon && board != ttt.OWon {
+			return fmt.Errorf("invalid record: termination is %v, but board is %v", r.Termination, board)
+		}
+	case BoardFull:
+		if board != ttt.Tie {
+			return fmt.Errorf("invalid record: termination is %v, but board is %v", r.Termination, board)
+		}
+	case Resignation, Agreement, Timeout, IllegalMove, Panic, PlayerError, Aborted:
+		if board != ttt.NotEnd {
+			return fmt.Errorf("invalid record: termination is %v, but board is %v", r.Termination, board)
+		}
+	default:
+		return fmt.Errorf("invalid record: missing termination")
+	}
+
+	var want []ttt.Condition
+	switch r.Termination {
+	case Line, BoardFull:
+		want = []ttt.Condition{board}
+	case Agreement:
+		want = []ttt.Condition{ttt.Tie}
+	case Aborted:
+		want = []ttt.Condition{ttt.NotEnd}
+	default:
+		// The player whose turn it was resigned or forfeited, so their opponent won.
+		want = []ttt.Condition{ttt.XWon}
+		if g.Turn().Opponent() == ttt.O {
+			want = []ttt.Condition{ttt.OWon}
+		}
+	}
+	for _, c := range want {
+		if r.Result == c {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid record: result is %v, but termination is %v", r.Result, r.Termination)
+}
+
+// Game replays the moves of record r and returns the resulting game.
+func (r Record) Game() (*ttt.Game, error) {
+	if r.First != ttt.X && r.First != ttt.O {
+		return nil, fmt.Errorf("invalid record: first mark must be X or O")
+	}
+	rules := ttt.Classic
+	if r.Rules != nil {
+		rules = *r.Rules
+	}
+	g, err := ttt.NewMNKGame(rules, r.First)
+	if err != nil {
+		return nil, fmt.Errorf("invalid record: %v", err)
+	}
+	for i, m := range r.Moves {
+		err := g.Apply(ttt.Ply{Mark: m.Mark, Move: m.Move, Time: m.Time})
+		if err != nil {
+			return nil, fmt.Errorf("invalid record: move %v: %v", i+1, err)
+		}
+	}
+	return g, nil
+}
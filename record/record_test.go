@@ -0,0 +1,79 @@
+package record_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+)
+
+func TestWriteRead(t *testing.T) {
+	start := time.Date(2019, 6, 20, 20, 0, 0, 0, time.UTC)
+	seed := int64(42)
+	want := record.Record{
+		Version: record.Version,
+		X:       record.Player{Name: "Random Player", Seed: &seed},
+		O:       record.Player{Name: "Perfect Player"},
+		First:   ttt.X,
+		Start:   start,
+		Moves: []record.Move{
+			{Mark: ttt.X, Move: 0, Time: start.Add(2 * time.Second), Think: 2 * time.Second},
+			{Mark: ttt.O, Move: 4, Time: start.Add(4 * time.Second), Think: 2 * time.Second},
+			{Mark: ttt.X, Move: 8, Time: start.Add(6 * time.Second), Think: 2 * time.Second},
+			{Mark: ttt.O, Move: 1, Time: start.Add(8 * time.Second), Think: 2 * time.Second},
+			{Mark: ttt.X, Move: 2, Time: start.Add(10 * time.Second), Think: 2 * time.Second},
+			{Mark: ttt.O, Move: 7, Time: start.Add(12 * time.Second), Think: 2 * time.Second},
+		},
+		Result:      ttt.OWon,
+		Termination: record.Line,
+	}
+
+	var buf bytes.Buffer
+	if err := record.Write(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := record.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"unknown field", `{"version":1,"first":"X","result":"tie","termination":"agreement","color":"red"}`},
+		{"unknown version", `{"version":2,"first":"X","result":"tie","termination":"agreement"}`},
+		{"trailing data", `{"version":1,"first":"X","result":"tie","termination":"agreement"} {}`},
+		{"no first player", `{"version":1,"result":"tie","termination":"agreement"}`},
+		{"no termination", `{"version":1,"first":"X","result":"tie"}`},
+		{"out of turn", `{"version":1,"first":"X","moves":[{"mark":"O","move":4}],"result":"tie","termination":"agreement"}`},
+		{"occupied cell", `{"version":1,"first":"X","moves":[{"mark":"X","move":4},{"mark":"O","move":4}],"result":"tie","termination":"agreement"}`},
+		{"no line", `{"version":1,"first":"X","moves":[{"mark":"X","move":4}],"result":"x_won","termination":"line"}`},
+		{"wrong result", `{"version":1,"first":"X","moves":[{"mark":"X","move":4}],"result":"tie","termination":"resignation"}`},
+		{"time travel", `{"version":1,"first":"X","start":"2019-06-20T20:00:00Z","moves":[{"mark":"X","move":4,"time":"2019-06-20T19:00:00Z"}],"result":"x_won","termination":"timeout"}`},
+		{"wrong winner", `{"version":1,"first":"X","moves":[{"mark":"X","move":4}],"result":"o_won","termination":"timeout"}`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Make sure the record is otherwise valid.
+			if tc.name == "unknown field" {
+				valid := strings.Replace(tc.in, `,"color":"red"`, "", 1)
+				if _, err := record.Read(strings.NewReader(valid)); err != nil {
+					t.Fatalf("valid record: %v", err)
+				}
+			}
+			if _, err := record.Read(strings.NewReader(tc.in)); err == nil {
+				t.Error("got nil error, want non-nil")
+			}
+		})
+	}
+}
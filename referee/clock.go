@@ -0,0 +1,80 @@
+package referee
+
+import (
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+// TimeControl is a chess-clock time control for a match.
+// Each player has a budget of time for all of their moves,
+// and their clock only runs during their own turns.
+// A player that runs out of time forfeits the match.
+//
+// The zero value means no time control.
+type TimeControl struct {
+	// Budget is the total time each player starts the match with.
+	Budget time.Duration
+
+	// Increment is added to a player's clock after each of their moves
+	// (Fischer increment).
+	Increment time.Duration
+
+	// Delay is how much of the time a player spends on each move
+	// is given back to them after the move (Bronstein delay).
+	// Moves made within Delay don't use up any of the budget,
+	// but unlike Increment, unused delay doesn't accumulate.
+	Delay time.Duration
+}
+
+// Clock is the time the players have left.
+type Clock struct {
+	X, O time.Duration
+}
+
+// Remaining returns the time left for the player with the given mark.
+func (c Clock) Remaining(mark ttt.State) time.Duration {
+	return *c.of(mark)
+}
+
+// of returns a pointer to the time left for the player with the given mark.
+func (c *Clock) of(mark ttt.State) *time.Duration {
+	switch mark {
+	case ttt.X:
+		return &c.X
+	case ttt.O:
+		return &c.O
+	default:
+		panic("unreachable")
+	}
+}
+
+// start returns a clock with the initial budget for both players.
+func (tc TimeControl) start() Clock {
+	return Clock{X: tc.Budget, O: tc.Budget}
+}
+
+// turnTime returns the time a player with remaining time left
+// has for their next move before they run out.
+func (tc TimeControl) turnTime(remaining time.Duration) time.Duration {
+	return remaining + tc.Delay
+}
+
+// charge returns the time a player with remaining time left has
+// after spending think on a move.
+func (tc TimeControl) charge(remaining, think time.Duration) time.Duration {
+	return tc.spend(remaining, think) + tc.Increment
+}
+
+// spend returns the time a player with remaining time left has
+// after thinking for think, not counting any increment.
+func (tc TimeControl) spend(remaining, think time.Duration) time.Duration {
+	if think > tc.Delay {
+		remaining -= think - tc.Delay
+	}
+	if remaining < 0 {
+		// The move was made in time, only just.
+		remaining = 0
+	}
+	return remaining
+}
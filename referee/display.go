@@ -0,0 +1,59 @@
+package referee
+
+import ttt "github.com/shurcooL/tictactoe"
+
+// Display shows the progress of a match.
+// The referee calls its methods as the match progresses,
+// from the goroutine that called Match.
+type Display interface {
+	// GameStart is called before the first turn,
+	// with the players of marks X and O.
+	GameStart(x, o ttt.Player)
+
+	// TurnStart is called at the start of each turn.
+	TurnStart(t Turn)
+
+	// TurnEnd is called after the player whose turn it is
+	// has made a valid and legal move.
+	TurnEnd(t Turn)
+
+	// GameEnd is called when the match is over, with its result.
+	// err is non-nil if the match ended because of an error,
+	// and is the same error that Match returns.
+	GameEnd(r Result, err error)
+}
+
+// DrawOfferDisplay is an optional interface implemented by displays
+// that show draw offers.
+type DrawOfferDisplay interface {
+	// DrawOffer is called when the player whose turn it is offers a draw,
+	// before the opponent decides whether to accept it.
+	DrawOffer(t Turn)
+
+	// DrawAnswer is called when the opponent has decided.
+	// If it declined, the turn goes on.
+	DrawAnswer(t Turn, accepted bool)
+}
+
+// Turn of a match.
+type Turn struct {
+	// Board at the start of the turn in TurnStart,
+	// or after the move was applied in TurnEnd.
+	// It's the zero Board if the match isn't played with ttt.Classic rules.
+	Board ttt.Board
+
+	// MNKBoard is the same board, with the rules of the match.
+	// Displays that show m,n,k-games with any rules use it.
+	MNKBoard ttt.MNKBoard
+
+	// Mark of the player whose turn it is.
+	Mark ttt.State
+
+	// Move made by the player. It's only set in TurnEnd.
+	Move Move
+
+	// Clock is the time the players had left at the start of the turn
+	// in TurnStart, or after the move was made in TurnEnd, including
+	// any increment. It's zero if the match has no time control.
+	Clock Clock
+}
@@ -0,0 +1,99 @@
+package referee
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+)
+
+// JSONLog is a display that writes a log of match events
+// as a stream of JSON objects, one per line.
+type JSONLog struct {
+	enc *json.Encoder
+	err error
+}
+
+// NewJSONLog creates a display that writes a JSON event log to w.
+func NewJSONLog(w io.Writer) *JSONLog {
+	return &JSONLog{enc: json.NewEncoder(w)}
+}
+
+// event is a single entry in a JSON event log.
+type event struct {
+	Event     string         `json:"event"` // One of "game_start", "turn_start", "turn_end" or "game_end".
+	Time      time.Time      `json:"time"`
+	X         string         `json:"x,omitempty"`         // Name of player X.
+	O         string         `json:"o,omitempty"`         // Name of player O.
+	XSeed     *int64         `json:"x_seed,omitempty"`    // Seed of player X, if known.
+	OSeed     *int64         `json:"o_seed,omitempty"`    // Seed of player O, if known.
+	Board     *ttt.Board     `json:"board,omitempty"`     // Board, if the match is played with ttt.Classic rules.
+	MNKBoard  *ttt.MNKBoard  `json:"mnk_board,omitempty"` // Board, if the match is played with other rules.
+	Mark      ttt.State      `json:"mark,omitempty"`
+	Move      *ttt.Move      `json:"move,omitempty"`
+	Think     time.Duration  `json:"think_ns,omitempty"`
+	Remaining time.Duration  `json:"remaining_ns,omitempty"` // Time the player whose turn it is has left, if there's a time control.
+	Condition *ttt.Condition `json:"condition,omitempty"`    // Condition of the board after a move.
+
+	Outcome     *ttt.Condition      `json:"outcome,omitempty"`     // Outcome of the match, see Result.Outcome.
+	Termination *record.Termination `json:"termination,omitempty"` // How the match ended.
+	Forfeit     string              `json:"forfeit,omitempty"`     // What the loser did to forfeit the match.
+	Error       string              `json:"error,omitempty"`
+}
+
+func (l *JSONLog) GameStart(x, o ttt.Player) {
+	l.log(event{Event: "game_start", Time: time.Now(), X: x.Name(), O: o.Name(), XSeed: seedOf(x), OSeed: seedOf(o)})
+}
+
+func (l *JSONLog) TurnStart(t Turn) {
+	e := event{Event: "turn_start", Time: time.Now(), Mark: t.Mark, Remaining: t.Clock.Remaining(t.Mark)}
+	e.setBoard(t.Board, t.MNKBoard)
+	l.log(e)
+}
+
+func (l *JSONLog) TurnEnd(t Turn) {
+	condition := t.MNKBoard.Condition()
+	e := event{Event: "turn_end", Time: t.Move.Time, Mark: t.Mark, Move: &t.Move.Move, Think: t.Move.Think, Remaining: t.Clock.Remaining(t.Mark), Condition: &condition}
+	e.setBoard(t.Board, t.MNKBoard)
+	l.log(e)
+}
+
+func (l *JSONLog) GameEnd(r Result, err error) {
+	outcome := r.Outcome()
+	e := event{Event: "game_end", Time: time.Now(), Outcome: &outcome}
+	e.setBoard(r.Board, r.MNKBoard)
+	if r.Termination != 0 {
+		e.Termination = &r.Termination
+	}
+	if r.Forfeit != nil {
+		e.Forfeit = r.Forfeit.Error()
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	l.log(e)
+}
+
+// setBoard sets the board of event e to b, or to mnk
+// if the match isn't played with ttt.Classic rules.
+func (e *event) setBoard(b ttt.Board, mnk ttt.MNKBoard) {
+	if mnk.Rules != ttt.Classic {
+		e.MNKBoard = &mnk
+		return
+	}
+	e.Board = &b
+}
+
+func (l *JSONLog) log(e event) {
+	if l.err != nil {
+		return
+	}
+	l.err = l.enc.Encode(e)
+}
+
+// Err returns the first error that occurred while writing the log, if any.
+func (l *JSONLog) Err() error {
+	return l.err
+}
@@ -0,0 +1,46 @@
+package referee
+
+import (
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+)
+
+// Recorder is a display that records a match as a game record.
+//
+// The zero value is ready to use.
+type Recorder struct {
+	rec  record.Record
+	done bool // Whether the match is over.
+}
+
+func (r *Recorder) GameStart(x, o ttt.Player) {
+	*r = Recorder{rec: record.Record{
+		Version: record.Version,
+		X:       record.Player{Name: x.Name()},
+		O:       record.Player{Name: o.Name()},
+	}}
+}
+
+func (r *Recorder) TurnStart(Turn) {}
+
+func (r *Recorder) TurnEnd(Turn) {}
+
+func (r *Recorder) GameEnd(res Result, err error) {
+	if rules := res.MNKBoard.Rules; rules != ttt.Classic {
+		r.rec.Rules = &rules
+	}
+	r.rec.X.Seed, r.rec.O.Seed = res.XSeed, res.OSeed
+	r.rec.First = res.First
+	r.rec.Start = res.Start
+	for _, m := range res.Moves {
+		r.rec.Moves = append(r.rec.Moves, record.Move{Mark: m.Mark, Move: m.Move, Time: m.Time, Think: m.Think})
+	}
+	r.rec.Result, r.rec.Termination = res.Outcome(), res.Termination
+	r.done = true
+}
+
+// Record returns the record of the match.
+// ok is false if the match isn't over yet.
+func (r *Recorder) Record() (rec record.Record, ok bool) {
+	return r.rec, r.done
+}
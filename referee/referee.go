@@ -0,0 +1,516 @@
+// Package referee runs games of tic-tac-toe between two players,
+// enforcing the rules and time limits.
+package referee
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/record"
+)
+
+// DefaultTimePerTurn is the time each player gets to think per turn,
+// unless overridden by Options.TimePerTurn.
+const DefaultTimePerTurn = 5 * time.Second
+
+// DefaultNotifyTimeout is how long a player is waited for when notified
+// about the game, unless overridden by Options.NotifyTimeout.
+const DefaultNotifyTimeout = time.Second
+
+// Options for a match.
+type Options struct {
+	// TimePerTurn is the time each player gets to think per turn.
+	// Zero means DefaultTimePerTurn, unless TimeControl is set,
+	// in which case turns are only limited by the players' clocks.
+	// Answering a draw offer is limited the same way, and it's done
+	// on the answering player's time, with the offering player's time stopped.
+	TimePerTurn time.Duration
+
+	// TimeControl, if set, gives each player a budget of time
+	// for the whole match, on top of the limit per turn.
+	TimeControl TimeControl
+
+	// MinTurnDuration is the minimum duration of a turn.
+	// If a player moves sooner, the referee waits out the rest of the turn
+	// so that spectators can follow the game. Zero means no minimum.
+	MinTurnDuration time.Duration
+
+	// NotifyTimeout is how long the referee waits for a player to return
+	// from StartGame, ObserveMove or EndGame before it carries on without it.
+	// A player that hasn't returned from a notification yet misses the next ones,
+	// so that it's never notified concurrently. Zero means DefaultNotifyTimeout.
+	NotifyTimeout time.Duration
+
+	// First is the mark of the player that moves first, either X or O.
+	// Zero value means X.
+	First ttt.State
+
+	// Rules of the game. Zero value means ttt.Classic.
+	// With other rules, both players must be ttt.MNKPlayers.
+	Rules ttt.Rules
+
+	// CellClick, if not nil, is a channel of indices of clicked board cells,
+	// in range [0, 9) on a classic board.
+	// Each click is passed on to the player whose turn it is,
+	// if that player is a ttt.CellClicker.
+	CellClick <-chan int
+
+	// Commands, if not nil, is a channel of commands given to players by their users.
+	// Resign and OfferDraw are passed on to the player whose turn it is,
+	// and AcceptDraw and DeclineDraw to the player that's been offered a draw,
+	// if that player is a ttt.Commander.
+	Commands <-chan ttt.Command
+
+	// Displays show the progress of the match.
+	Displays []Display
+}
+
+// Move made by a player during a match.
+type Move struct {
+	ttt.Ply
+	Think time.Duration // Time the player took to make the move.
+}
+
+// Result of a match.
+type Result struct {
+	Board       ttt.Board          // Board at the end of the match, if it's played with ttt.Classic rules.
+	MNKBoard    ttt.MNKBoard       // Board at the end of the match, with the rules of the match.
+	Condition   ttt.Condition      // Condition of the board at the end of the match.
+	Winner      ttt.State          // Mark of the winner, or F if the match was drawn or stopped.
+	Termination record.Termination // How the match ended.
+	Forfeit     error              // What the loser did to forfeit the match, if Termination is a forfeit.
+	Clock       Clock              // Time the players had left at the end of the match, if there's a time control.
+	First       ttt.State          // Mark of the player that moved first.
+	Start       time.Time          // Time when the match started.
+	XSeed       *int64             // Seed of player X, if it's a ttt.Seeder that knows it.
+	OSeed       *int64             // Seed of player O, if it's a ttt.Seeder that knows it.
+	Moves       []Move             // Moves made by the players, in order.
+}
+
+// Outcome returns the outcome of the match: XWon or OWon if a player won,
+// Tie if the match was drawn, or NotEnd if it was stopped before it ended.
+// It differs from Condition when the match ended before the board did,
+// e.g., because a player resigned.
+func (r Result) Outcome() ttt.Condition {
+	switch {
+	case r.Winner == ttt.X:
+		return ttt.XWon
+	case r.Winner == ttt.O:
+		return ttt.OWon
+	case r.Termination == record.BoardFull || r.Termination == record.Agreement:
+		return ttt.Tie
+	default:
+		return ttt.NotEnd
+	}
+}
+
+// Match plays a game of tic-tac-toe between players x and o
+// until the end (Condition != ttt.NotEnd), until a player resigns,
+// forfeits or the players agree to a draw, or until ctx is done.
+//
+// A player that fails to make a valid and legal move in time forfeits,
+// and their opponent wins. It happens when the player takes more than
+// the allotted time to move, makes an illegal move, panics, or returns
+// an error from Play. The result's Termination says which one it was.
+//
+// If ctx is done before the game ends, Match returns ctx.Err()
+// along with the result of the game so far. If opt.Rules aren't valid,
+// or a player can't play with them, Match returns an error without playing.
+func Match(ctx context.Context, x, o ttt.Player, opt Options) (Result, error) {
+	if opt.TimePerTurn == 0 && opt.TimeControl == (TimeControl{}) {
+		opt.TimePerTurn = DefaultTimePerTurn
+	}
+	if opt.NotifyTimeout == 0 {
+		opt.NotifyTimeout = DefaultNotifyTimeout
+	}
+	if opt.First == ttt.F {
+		opt.First = ttt.X
+	}
+	if opt.Rules == (ttt.Rules{}) {
+		opt.Rules = ttt.Classic
+	}
+	// Start with an empty board.
+	game, err := ttt.NewMNKGame(opt.Rules, opt.First)
+	if err != nil {
+		return Result{}, err
+	}
+	if opt.Rules != ttt.Classic {
+		for _, p := range []ttt.Player{x, o} {
+			if _, ok := p.(ttt.MNKPlayer); !ok {
+				return Result{}, fmt.Errorf("player %s can't play with %v rules", p.Name(), opt.Rules)
+			}
+		}
+	}
+
+	for _, d := range opt.Displays {
+		d.GameStart(x, o)
+	}
+	n := notifier{timeout: opt.NotifyTimeout}
+	if p, ok := x.(ttt.GameStarter); ok {
+		n.notify(0, func() { p.StartGame(ttt.X, opt.First) })
+	}
+	if p, ok := o.(ttt.GameStarter); ok {
+		n.notify(1, func() { p.StartGame(ttt.O, opt.First) })
+	}
+
+	result, err := match(ctx, game, x, o, &n, opt)
+
+	for _, d := range opt.Displays {
+		d.GameEnd(result, err)
+	}
+	for i, p := range []ttt.Player{x, o} {
+		if p, ok := p.(ttt.GameEnder); ok {
+			n.notify(i, func() { p.EndGame(result.Board, result.Outcome()) })
+		}
+	}
+	return result, err
+}
+
+func match(ctx context.Context, game *ttt.Game, x, o ttt.Player, n *notifier, opt Options) (Result, error) {
+	result := Result{Board: board(game), MNKBoard: game.MNKBoard(), First: opt.First, Start: time.Now(), XSeed: seedOf(x), OSeed: seedOf(o)}
+	if opt.TimeControl != (TimeControl{}) {
+		result.Clock = opt.TimeControl.start()
+	}
+
+	for game.Condition() == ttt.NotEnd {
+		mark := game.Turn()
+		p, opponent := x, o
+		if mark == ttt.O {
+			p, opponent = o, x
+		}
+		for _, d := range opt.Displays {
+			d.TurnStart(Turn{Board: board(game), MNKBoard: game.MNKBoard(), Mark: mark, Clock: result.Clock})
+		}
+
+		timeout, onClock := turnTimeout(opt, result.Clock, mark)
+		answerTimeout, _ := turnTimeout(opt, result.Clock, mark.Opponent())
+		turnStart := time.Now()
+
+		ply, answering, err := playerTurn(ctx, game, p, opponent, mark, timeout, answerTimeout, opt)
+		result.Board, result.MNKBoard, result.Condition = board(game), game.MNKBoard(), game.Condition()
+		if answering > 0 && opt.TimeControl != (TimeControl{}) {
+			// The opponent answered a draw offer on its own clock.
+			remaining := result.Clock.of(mark.Opponent())
+			*remaining = opt.TimeControl.spend(*remaining, answering)
+		}
+		switch {
+		case err == nil:
+		case err == ttt.ErrResign:
+			result.Winner, result.Termination = mark.Opponent(), record.Resignation
+			return result, nil
+		case err == errDrawAgreed:
+			result.Termination = record.Agreement
+			return result, nil
+		case err == ctx.Err():
+			result.Termination = record.Aborted
+			return result, err
+		default:
+			f := err.(*forfeit)
+			if f.Termination == record.Timeout && opt.TimeControl != (TimeControl{}) {
+				remaining := result.Clock.of(mark)
+				if onClock {
+					*remaining = 0
+				} else {
+					// The player ran out of time per turn, with time left on the clock.
+					*remaining = opt.TimeControl.spend(*remaining, timeout)
+				}
+			}
+			result.Winner, result.Termination, result.Forfeit = mark.Opponent(), f.Termination, f.Err
+			return result, nil
+		}
+		move := Move{Ply: ply, Think: ply.Time.Sub(turnStart) - answering}
+		result.Moves = append(result.Moves, move)
+		if opt.TimeControl != (TimeControl{}) {
+			remaining := result.Clock.of(mark)
+			*remaining = opt.TimeControl.charge(*remaining, move.Think)
+		}
+
+		for i, p := range []ttt.Player{x, o} {
+			if p, ok := p.(ttt.MoveObserver); ok {
+				b := result.Board
+				n.notify(i, func() { p.ObserveMove(b, mark, move.Move) })
+			}
+		}
+
+		for _, d := range opt.Displays {
+			d.TurnEnd(Turn{Board: result.Board, MNKBoard: result.MNKBoard, Mark: mark, Move: move, Clock: result.Clock})
+		}
+
+		// Enforce the minimum turn duration.
+		if untilTurnEnd := opt.MinTurnDuration - time.Since(turnStart); untilTurnEnd > 0 {
+			select {
+			case <-time.After(untilTurnEnd):
+			case <-ctx.Done():
+				result.Termination = record.Aborted
+				return result, ctx.Err()
+			}
+		}
+	}
+
+	// At this point, the game is over.
+	switch result.Condition {
+	case ttt.XWon:
+		result.Winner, result.Termination = ttt.X, record.Line
+	case ttt.OWon:
+		result.Winner, result.Termination = ttt.O, record.Line
+	case ttt.Tie:
+		result.Termination = record.BoardFull
+	}
+	return result, nil
+}
+
+// turnTimeout returns the time the player with the given mark has for a turn,
+// given the time left on clock, and whether the player's clock limits the turn.
+func turnTimeout(opt Options, clock Clock, mark ttt.State) (timeout time.Duration, onClock bool) {
+	timeout = opt.TimePerTurn
+	if opt.TimeControl != (TimeControl{}) {
+		if t := opt.TimeControl.turnTime(clock.Remaining(mark)); timeout == 0 || t < timeout {
+			timeout, onClock = t, true
+		}
+	}
+	return timeout, onClock
+}
+
+// errDrawAgreed is returned by playerTurn when the players agreed to a draw.
+var errDrawAgreed = errors.New("draw agreed")
+
+// playerTurn gets player p's move and applies it to game g,
+// giving p up to timeout to make it.
+// If p offers a draw, the opponent gets up to answerTimeout to answer it,
+// and p's time is stopped meanwhile. playerTurn also returns how long
+// the opponent took to answer, which isn't part of p's turn.
+// If p resigns, or offers a draw that the opponent accepts,
+// it returns ttt.ErrResign or errDrawAgreed, respectively.
+// If p forfeits, it returns a *forfeit error.
+func playerTurn(ctx context.Context, g *ttt.Game, p, opponent ttt.Player, mark ttt.State, timeout, answerTimeout time.Duration, opt Options) (_ ttt.Ply, answering time.Duration, _ error) {
+	start := time.Now()
+	var (
+		move ttt.Move
+		err  error = &forfeit{Termination: record.Timeout}
+	)
+	if timeout > 0 {
+		move, err = playerMove(ctx, g, p, mark, timeout, opt)
+	}
+	if err == ttt.ErrOfferDraw {
+		left := timeout - time.Since(start)
+		turn := Turn{Board: board(g), MNKBoard: g.MNKBoard(), Mark: mark}
+		for _, d := range opt.Displays {
+			if d, ok := d.(DrawOfferDisplay); ok {
+				d.DrawOffer(turn)
+			}
+		}
+		answerStart := time.Now()
+		accepted := acceptDraw(ctx, board(g), opponent, mark.Opponent(), answerTimeout, opt.Commands)
+		answering = time.Since(answerStart)
+		for _, d := range opt.Displays {
+			if d, ok := d.(DrawOfferDisplay); ok {
+				d.DrawAnswer(turn, accepted)
+			}
+		}
+		if accepted {
+			return ttt.Ply{}, answering, errDrawAgreed
+		}
+		// The offer was declined, so it's still p's turn to move,
+		// with the time it had left when it made the offer.
+		if left <= 0 {
+			err = &forfeit{Termination: record.Timeout}
+		} else if move, err = playerMove(ctx, g, p, mark, left, opt); err == ttt.ErrOfferDraw {
+			err = fmt.Errorf("offered a draw more than once in a turn")
+		}
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		// The match was canceled.
+		return ttt.Ply{}, answering, ctxErr
+	} else if err == ttt.ErrResign {
+		return ttt.Ply{}, answering, err
+	} else if f, ok := err.(*forfeit); ok {
+		if f.Termination == record.Timeout {
+			// Report the time for the whole turn, rather than what was left of it after a draw offer.
+			f.Err = fmt.Errorf("took more than allotted time of %v", timeout)
+		}
+		f.Err = fmt.Errorf("player %v (%s) failed to make a move: %v", mark, p.Name(), f.Err)
+		return ttt.Ply{}, answering, f
+	} else if err != nil {
+		return ttt.Ply{}, answering, &forfeit{
+			Termination: record.PlayerError,
+			Err:         fmt.Errorf("player %v (%s) failed to make a move: %v", mark, p.Name(), err),
+		}
+	}
+
+	ply := ttt.Ply{Mark: mark, Move: move, Time: time.Now()}
+	err = g.Apply(ply)
+	if err != nil {
+		return ttt.Ply{}, answering, &forfeit{
+			Termination: record.IllegalMove,
+			Err:         fmt.Errorf("player %v (%s) made a move that isn't valid or isn't legal: %v", mark, p.Name(), err),
+		}
+	}
+
+	return ply, answering, nil
+}
+
+// forfeit is an error made by a player that forfeits the match.
+type forfeit struct {
+	Termination record.Termination // Timeout, IllegalMove, Panic or PlayerError.
+	Err         error
+}
+
+func (f *forfeit) Error() string { return f.Err.Error() }
+
+// acceptDraw asks player p with the given mark whether it accepts a draw.
+// Players that aren't ttt.DrawAccepters, take longer than timeout
+// to decide, or panic, decline. AcceptDraw and DeclineDraw commands
+// are passed on to p while it decides.
+func acceptDraw(ctx context.Context, b ttt.Board, p ttt.Player, mark ttt.State, timeout time.Duration, commands <-chan ttt.Command) bool {
+	accepter, ok := p.(ttt.DrawAccepter)
+	if !ok {
+		return false
+	}
+	acceptCh := make(chan bool, 1)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				acceptCh <- false
+			}
+		}()
+		acceptCh <- accepter.AcceptDraw(ctx, b, mark)
+	}()
+
+	for {
+		select {
+		case accept := <-acceptCh:
+			return accept
+		case c := <-commands:
+			if p, ok := p.(ttt.Commander); ok && (c == ttt.AcceptDraw || c == ttt.DeclineDraw) {
+				p.Command(c)
+			}
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// playerMove gets player p's move on the board of game g, enforcing the timeout.
+// Cell clicks and Resign and OfferDraw commands in opt are passed on to p.
+func playerMove(ctx context.Context, g *ttt.Game, p ttt.Player, mark ttt.State, timeout time.Duration, opt Options) (ttt.Move, error) {
+	type moveError struct {
+		ttt.Move
+		err error
+	}
+	resultCh := make(chan moveError, 1)
+
+	// Take the board now, since the player may still
+	// be playing on it after the game has moved on.
+	var play func(ctx context.Context) (ttt.Move, error)
+	if g.Rules() == ttt.Classic {
+		b := g.Board()
+		play = func(ctx context.Context) (ttt.Move, error) { return p.Play(ctx, b, mark) }
+	} else {
+		b := g.MNKBoard()
+		play = func(ctx context.Context) (ttt.Move, error) { return p.(ttt.MNKPlayer).PlayMNK(ctx, b, mark) }
+	}
+
+	turnCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// We can't trust the player not to misbehave and just ignore the timeout, causing
+	// the game to stall. So we let it play inside a goroutine, and monitor ctx.Done()
+	// channel ourselves. No one wants a slowpoke to hold the game up! :) Also catch panics.
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				resultCh <- moveError{err: &forfeit{Termination: record.Panic, Err: fmt.Errorf("panic: %v", e)}}
+			}
+		}()
+		move, err := play(turnCtx)
+		resultCh <- moveError{move, err}
+	}()
+
+	for {
+		select {
+		case result := <-resultCh:
+			return result.Move, result.err
+		case index := <-opt.CellClick:
+			if p, ok := p.(ttt.CellClicker); ok {
+				p.CellClick(index)
+			}
+		case c := <-opt.Commands:
+			if p, ok := p.(ttt.Commander); ok && (c == ttt.Resign || c == ttt.OfferDraw) {
+				p.Command(c)
+			}
+		case <-turnCtx.Done():
+			return 0, &forfeit{Termination: record.Timeout, Err: fmt.Errorf("took more than allotted time of %v", timeout)}
+		}
+	}
+}
+
+// board returns the board of game g, or the zero Board
+// if g isn't played with ttt.Classic rules.
+func board(g *ttt.Game) ttt.Board {
+	if g.Rules() != ttt.Classic {
+		return ttt.Board{}
+	}
+	return g.Board()
+}
+
+// notifier notifies the players about the game, without letting
+// a player that doesn't return hold up the match.
+type notifier struct {
+	timeout time.Duration
+	pending [2]chan struct{} // Closed when the last notification of player X or O, respectively, returns.
+}
+
+// notify calls f, which notifies player X if i is 0, or player O if i is 1,
+// and waits up to n.timeout for it to return, ignoring any panics.
+// If that player hasn't returned from an earlier notification,
+// f isn't called at all.
+func (n *notifier) notify(i int, f func()) {
+	if n.pending[i] != nil {
+		select {
+		case <-n.pending[i]:
+		default:
+			return
+		}
+	}
+	done := make(chan struct{})
+	n.pending[i] = done
+	go func() {
+		defer close(done)
+		safely(f)
+	}()
+
+	t := time.NewTimer(n.timeout)
+	defer t.Stop()
+	select {
+	case <-done:
+	case <-t.C:
+	}
+}
+
+// seedOf returns the seed of player p,
+// or nil if p isn't a ttt.Seeder or doesn't know its seed.
+func seedOf(p ttt.Player) *int64 {
+	s, ok := p.(ttt.Seeder)
+	if !ok {
+		return nil
+	}
+	seed, ok := s.Seed()
+	if !ok {
+		return nil
+	}
+	return &seed
+}
+
+// safely calls f, recovering and ignoring any panics.
+// It's used to notify players, who can't be trusted not to panic.
+func safely(f func()) {
+	defer func() { _ = recover() }()
+	f()
+}
@@ -0,0 +1,615 @@
+package referee_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/player/human"
+	"github.com/shurcooL/tictactoe/record"
+	"github.com/shurcooL/tictactoe/referee"
+)
+
+// firstPlayer always plays the first legal move.
+type firstPlayer struct{}
+
+func (firstPlayer) Name() string { return "First Player" }
+
+func (firstPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	return b.LegalMoves()[0], nil
+}
+
+// firstMNKPlayer always plays the first legal move, on a board of any size.
+type firstMNKPlayer struct{ firstPlayer }
+
+func (firstMNKPlayer) PlayMNK(ctx context.Context, b ttt.MNKBoard, mark ttt.State) (ttt.Move, error) {
+	return b.LegalMoves()[0], nil
+}
+
+// funcPlayer plays by calling a function.
+type funcPlayer func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error)
+
+func (funcPlayer) Name() string { return "Func Player" }
+
+func (f funcPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	return f(ctx, b, mark)
+}
+
+func TestMatch(t *testing.T) {
+	var (
+		turns    turnDisplay
+		recorder referee.Recorder
+		events   bytes.Buffer
+	)
+	log := referee.NewJSONLog(&events)
+	result, err := referee.Match(context.Background(), firstPlayer{}, firstPlayer{}, referee.Options{
+		First:    ttt.O,
+		Displays: []referee.Display{&turns, &recorder, log},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// O moves first and completes the left column first.
+	want := ttt.Board{
+		Cells: [9]ttt.State{
+			ttt.O, ttt.X, ttt.O,
+			ttt.X, ttt.O, ttt.X,
+			ttt.O, ttt.F, ttt.F,
+		},
+	}
+	if result.Board != want {
+		t.Errorf("got board\n%v\nwant\n%v", result.Board, want)
+	}
+	if got, want := result.Condition, ttt.OWon; got != want {
+		t.Errorf("got condition %v, want %v", got, want)
+	}
+	if got, want := len(result.Moves), 7; got != want {
+		t.Errorf("got %v moves, want %v", got, want)
+	}
+	if got, want := len(turns), 7; got != want || turns[0] != ttt.O || turns[1] != ttt.X {
+		t.Errorf("got turns %v, want %v alternating turns starting with O", turns, want)
+	}
+
+	rec, ok := recorder.Record()
+	if !ok {
+		t.Fatal("recorder has no record after the match")
+	}
+	if err := rec.Verify(); err != nil {
+		t.Error(err)
+	}
+	if got, want := rec.Termination, record.Line; got != want {
+		t.Errorf("got termination %v, want %v", got, want)
+	}
+
+	if err := log.Err(); err != nil {
+		t.Fatal(err)
+	}
+	// One event for game start and end, and two for each turn.
+	if got, want := strings.Count(events.String(), "\n"), 2+2*7; got != want {
+		t.Errorf("got %v events, want %v:\n%s", got, want, events.String())
+	}
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	if gameEnd := lines[len(lines)-1]; !strings.Contains(gameEnd, `"outcome":"o_won"`) || !strings.Contains(gameEnd, `"termination":"line"`) {
+		t.Errorf("game end event %s doesn't have outcome %v and termination %v", gameEnd, ttt.OWon, record.Line)
+	}
+}
+
+func TestMatchMNK(t *testing.T) {
+	rules := ttt.Rules{Width: 4, Height: 4, K: 4}
+	var recorder referee.Recorder
+	result, err := referee.Match(context.Background(), firstMNKPlayer{}, firstMNKPlayer{}, referee.Options{
+		Rules:    rules,
+		Displays: []referee.Display{&recorder},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// X fills the even cells, and completes the left column first.
+	if got, want := result.Condition, ttt.XWon; got != want {
+		t.Errorf("got condition %v, want %v", got, want)
+	}
+	if got, want := len(result.Moves), 13; got != want {
+		t.Errorf("got %v moves, want %v", got, want)
+	}
+	if got, want := result.MNKBoard.Rules, rules; got != want {
+		t.Errorf("got rules %v, want %v", got, want)
+	}
+	if result.Board != (ttt.Board{}) {
+		t.Errorf("got board\n%v\nwant the zero board in a game with %v rules", result.Board, rules)
+	}
+
+	rec, ok := recorder.Record()
+	if !ok {
+		t.Fatal("recorder has no record after the match")
+	}
+	if rec.Rules == nil || *rec.Rules != rules {
+		t.Errorf("got record rules %v, want %v", rec.Rules, rules)
+	}
+	if err := rec.Verify(); err != nil {
+		t.Error(err)
+	}
+
+	_, err = referee.Match(context.Background(), firstMNKPlayer{}, firstPlayer{}, referee.Options{Rules: rules})
+	if err == nil {
+		t.Error("got no error for a player that can't play with other than classic rules")
+	}
+}
+
+// turnDisplay is a display that keeps track of whose turn it was.
+type turnDisplay []ttt.State
+
+func (*turnDisplay) GameStart(x, o ttt.Player)           {}
+func (d *turnDisplay) TurnStart(t referee.Turn)          { *d = append(*d, t.Mark) }
+func (*turnDisplay) TurnEnd(referee.Turn)                {}
+func (*turnDisplay) GameEnd(r referee.Result, err error) {}
+
+func TestMatchForfeits(t *testing.T) {
+	tests := []struct {
+		name            string
+		player          ttt.Player
+		wantTermination record.Termination
+		wantErr         string
+	}{
+		{
+			name: "timeout",
+			player: funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+				time.Sleep(100 * time.Millisecond)
+				return b.LegalMoves()[0], nil
+			}),
+			wantTermination: record.Timeout,
+			wantErr:         "took more than allotted time",
+		},
+		{
+			name: "panic",
+			player: funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+				panic("oops")
+			}),
+			wantTermination: record.Panic,
+			wantErr:         "panic: oops",
+		},
+		{
+			name: "illegal move",
+			player: funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+				return 0, nil
+			}),
+			wantTermination: record.IllegalMove,
+			wantErr:         "isn't valid or isn't legal",
+		},
+		{
+			name: "error",
+			player: funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+				return 0, fmt.Errorf("no idea")
+			}),
+			wantTermination: record.PlayerError,
+			wantErr:         "failed to make a move: no idea",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var recorder referee.Recorder
+			result, err := referee.Match(context.Background(), firstPlayer{}, tc.player, referee.Options{
+				TimePerTurn: 10 * time.Millisecond,
+				Displays:    []referee.Display{&recorder},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Winner != ttt.X || result.Termination != tc.wantTermination {
+				t.Errorf("got winner %q by %v, want %q by %v", result.Winner, result.Termination, ttt.X, tc.wantTermination)
+			}
+			if result.Forfeit == nil || !strings.Contains(result.Forfeit.Error(), tc.wantErr) {
+				t.Errorf("got forfeit %v, want one containing %q", result.Forfeit, tc.wantErr)
+			}
+			if got, want := len(result.Moves), 1; got != want {
+				t.Errorf("got %v moves, want %v", got, want)
+			}
+			rec, _ := recorder.Record()
+			if err := rec.Verify(); err != nil {
+				t.Error(err)
+			}
+			if rec.Result != ttt.XWon {
+				t.Errorf("got record result %v, want %v", rec.Result, ttt.XWon)
+			}
+		})
+	}
+}
+
+func TestMatchCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := referee.Match(ctx, firstPlayer{}, firstPlayer{}, referee.Options{})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+// observingPlayer plays the first legal move,
+// and keeps track of what it was notified about.
+type observingPlayer struct {
+	firstPlayer
+	mark, first ttt.State
+	moves       []ttt.Move
+	result      ttt.Condition
+}
+
+func (p *observingPlayer) StartGame(mark, first ttt.State) { p.mark, p.first = mark, first }
+func (p *observingPlayer) ObserveMove(b ttt.Board, mark ttt.State, move ttt.Move) {
+	p.moves = append(p.moves, move)
+}
+func (p *observingPlayer) EndGame(b ttt.Board, result ttt.Condition) { p.result = result }
+
+func TestMatchNotifications(t *testing.T) {
+	x, o := &observingPlayer{}, &observingPlayer{}
+	result, err := referee.Match(context.Background(), x, o, referee.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		p    *observingPlayer
+		mark ttt.State
+	}{{x, ttt.X}, {o, ttt.O}} {
+		if tc.p.mark != tc.mark || tc.p.first != ttt.X {
+			t.Errorf("player %v: got StartGame(%v, %v), want StartGame(%v, %v)", tc.mark, tc.p.mark, tc.p.first, tc.mark, ttt.X)
+		}
+		if got, want := len(tc.p.moves), len(result.Moves); got != want {
+			t.Errorf("player %v: observed %v moves, want %v", tc.mark, got, want)
+		}
+		if got, want := tc.p.result, ttt.XWon; got != want {
+			t.Errorf("player %v: got result %v, want %v", tc.mark, got, want)
+		}
+	}
+}
+
+// blockingPlayer plays the first legal move,
+// but doesn't return from ObserveMove or EndGame until release is closed.
+type blockingPlayer struct {
+	firstPlayer
+	release  chan struct{}
+	observed int32 // Number of ObserveMove calls, accessed atomically.
+}
+
+func (p *blockingPlayer) ObserveMove(b ttt.Board, mark ttt.State, move ttt.Move) {
+	atomic.AddInt32(&p.observed, 1)
+	<-p.release
+}
+func (p *blockingPlayer) EndGame(b ttt.Board, result ttt.Condition) { <-p.release }
+
+func TestMatchBlockingNotifications(t *testing.T) {
+	x := &blockingPlayer{release: make(chan struct{})}
+	defer close(x.release)
+	done := make(chan struct{})
+	var (
+		result referee.Result
+		err    error
+	)
+	go func() {
+		defer close(done)
+		result, err = referee.Match(context.Background(), x, firstPlayer{}, referee.Options{NotifyTimeout: 10 * time.Millisecond})
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("match is held up by a player that blocks in notifications")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := result.Condition, ttt.XWon; got != want {
+		t.Errorf("got condition %v, want %v", got, want)
+	}
+	// The first ObserveMove call is still blocked, so the player isn't notified about later moves.
+	if got := atomic.LoadInt32(&x.observed); got != 1 {
+		t.Errorf("ObserveMove was called %v times, want 1", got)
+	}
+}
+
+// drawPlayer offers a draw on every turn, and accepts draw offers if accept is set.
+type drawPlayer struct {
+	firstPlayer
+	accept bool
+	offers int // Number of draw offers made in the current turn.
+}
+
+func (p *drawPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	if p.offers == 0 {
+		p.offers++
+		return 0, ttt.ErrOfferDraw
+	}
+	p.offers = 0
+	return p.firstPlayer.Play(ctx, b, mark)
+}
+
+func (p *drawPlayer) AcceptDraw(ctx context.Context, b ttt.Board, mark ttt.State) bool {
+	return p.accept
+}
+
+func TestMatchResignAndDraw(t *testing.T) {
+	resigner := funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+		return 0, ttt.ErrResign
+	})
+	repeatOfferer := funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+		return 0, ttt.ErrOfferDraw
+	})
+	tests := []struct {
+		name            string
+		x, o            ttt.Player
+		wantMoves       int
+		wantWinner      ttt.State
+		wantTermination record.Termination
+	}{
+		{
+			name:            "resignation",
+			x:               firstPlayer{},
+			o:               resigner,
+			wantMoves:       1,
+			wantWinner:      ttt.X,
+			wantTermination: record.Resignation,
+		},
+		{
+			name:            "draw accepted",
+			x:               &drawPlayer{},
+			o:               &drawPlayer{accept: true},
+			wantMoves:       0,
+			wantTermination: record.Agreement,
+		},
+		{
+			name:            "draw declined",
+			x:               &drawPlayer{},
+			o:               &drawPlayer{},
+			wantMoves:       7,
+			wantWinner:      ttt.X,
+			wantTermination: record.Line,
+		},
+		{
+			name:            "draw offered twice",
+			x:               repeatOfferer,
+			o:               firstPlayer{},
+			wantWinner:      ttt.O,
+			wantTermination: record.PlayerError,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := referee.Match(context.Background(), tc.x, tc.o, referee.Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(result.Moves), tc.wantMoves; got != want {
+				t.Errorf("got %v moves, want %v", got, want)
+			}
+			if result.Winner != tc.wantWinner || result.Termination != tc.wantTermination {
+				t.Errorf("got winner %q by %v, want %q by %v", result.Winner, result.Termination, tc.wantWinner, tc.wantTermination)
+			}
+		})
+	}
+}
+
+// stallingPlayer plays the first legal move, and takes
+// all the time it has to decline draw offers.
+type stallingPlayer struct{ firstPlayer }
+
+func (stallingPlayer) AcceptDraw(ctx context.Context, b ttt.Board, mark ttt.State) bool {
+	<-ctx.Done()
+	return false
+}
+
+func TestMatchStalledDrawAnswer(t *testing.T) {
+	t.Run("time per turn", func(t *testing.T) {
+		result, err := referee.Match(context.Background(), &drawPlayer{}, stallingPlayer{}, referee.Options{
+			TimePerTurn: 50 * time.Millisecond,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Winner != ttt.X || result.Termination != record.Line {
+			t.Fatalf("got winner %q by %v (%v), want %q by %v", result.Winner, result.Termination, result.Forfeit, ttt.X, record.Line)
+		}
+		for _, m := range result.Moves {
+			if m.Mark == ttt.X && m.Think >= 25*time.Millisecond {
+				t.Errorf("got think time %v for move %v of player X, want it to exclude the time taken to answer its draw offer", m.Think, m.Move)
+			}
+		}
+	})
+	t.Run("time control", func(t *testing.T) {
+		budget := 100 * time.Millisecond
+		result, err := referee.Match(context.Background(), &drawPlayer{}, stallingPlayer{}, referee.Options{
+			TimeControl: referee.TimeControl{Budget: budget},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Player O used up its clock answering the draw offer.
+		if result.Winner != ttt.X || result.Termination != record.Timeout {
+			t.Fatalf("got winner %q by %v (%v), want %q by %v", result.Winner, result.Termination, result.Forfeit, ttt.X, record.Timeout)
+		}
+		if result.Clock.X < budget/2 {
+			t.Errorf("got %v left on player X's clock, want it not to run while player O answers", result.Clock.X)
+		}
+		if result.Clock.O != 0 {
+			t.Errorf("got %v left on player O's clock, want 0", result.Clock.O)
+		}
+	})
+}
+
+// commandDisplay gives players commands: the player whose turn it is
+// is given onTurn, and the player who's offered a draw is given onOffer.
+type commandDisplay struct {
+	commands        chan<- ttt.Command
+	onTurn, onOffer ttt.Command
+}
+
+func (commandDisplay) GameStart(x, o ttt.Player)                {}
+func (d commandDisplay) TurnStart(referee.Turn)                 { go func() { d.commands <- d.onTurn }() }
+func (commandDisplay) TurnEnd(referee.Turn)                     {}
+func (commandDisplay) GameEnd(r referee.Result, err error)      {}
+func (d commandDisplay) DrawOffer(referee.Turn)                 { go func() { d.commands <- d.onOffer }() }
+func (commandDisplay) DrawAnswer(t referee.Turn, accepted bool) {}
+
+func TestMatchCommands(t *testing.T) {
+	tests := []struct {
+		name            string
+		onTurn, onOffer ttt.Command
+		wantWinner      ttt.State
+		wantTermination record.Termination
+	}{
+		{
+			name:            "resign",
+			onTurn:          ttt.Resign,
+			wantWinner:      ttt.O,
+			wantTermination: record.Resignation,
+		},
+		{
+			name:            "draw accepted",
+			onTurn:          ttt.OfferDraw,
+			onOffer:         ttt.AcceptDraw,
+			wantTermination: record.Agreement,
+		},
+		{
+			name:            "draw declined",
+			onTurn:          ttt.OfferDraw,
+			onOffer:         ttt.DeclineDraw,
+			wantWinner:      ttt.O,
+			wantTermination: record.Timeout, // X doesn't move after the offer is declined.
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			x, err := human.NewPlayer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, err := human.NewPlayer()
+			if err != nil {
+				t.Fatal(err)
+			}
+			commands := make(chan ttt.Command)
+			result, err := referee.Match(context.Background(), x, o, referee.Options{
+				TimePerTurn: 100 * time.Millisecond,
+				Commands:    commands,
+				Displays:    []referee.Display{commandDisplay{commands: commands, onTurn: tc.onTurn, onOffer: tc.onOffer}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Winner != tc.wantWinner || result.Termination != tc.wantTermination {
+				t.Errorf("got winner %q by %v, want %q by %v", result.Winner, result.Termination, tc.wantWinner, tc.wantTermination)
+			}
+		})
+	}
+}
+
+func TestMatchTimeControl(t *testing.T) {
+	// slowPlayer takes 30ms to play the first legal move.
+	slowPlayer := funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+		time.Sleep(30 * time.Millisecond)
+		return b.LegalMoves()[0], nil
+	})
+	tests := []struct {
+		name            string
+		tc              referee.TimeControl
+		wantWinner      ttt.State
+		wantTermination record.Termination
+	}{
+		{
+			name:            "budget",
+			tc:              referee.TimeControl{Budget: 75 * time.Millisecond},
+			wantWinner:      ttt.O, // X runs out of time on its third move.
+			wantTermination: record.Timeout,
+		},
+		{
+			name:            "increment",
+			tc:              referee.TimeControl{Budget: 75 * time.Millisecond, Increment: 40 * time.Millisecond},
+			wantWinner:      ttt.X,
+			wantTermination: record.Line,
+		},
+		{
+			name:            "delay",
+			tc:              referee.TimeControl{Budget: 75 * time.Millisecond, Delay: 40 * time.Millisecond},
+			wantWinner:      ttt.X,
+			wantTermination: record.Line,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var clocks []referee.Clock
+			result, err := referee.Match(context.Background(), slowPlayer, slowPlayer, referee.Options{
+				TimeControl: tc.tc,
+				Displays:    []referee.Display{clockDisplay(func(c referee.Clock) { clocks = append(clocks, c) })},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Winner != tc.wantWinner || result.Termination != tc.wantTermination {
+				t.Errorf("got winner %q by %v (%v), want %q by %v", result.Winner, result.Termination, result.Forfeit, tc.wantWinner, tc.wantTermination)
+			}
+			if len(clocks) == 0 || clocks[0] != (referee.Clock{X: tc.tc.Budget, O: tc.tc.Budget}) {
+				t.Errorf("got clocks %v, want them to start at %v", clocks, tc.tc.Budget)
+			}
+			if tc.wantTermination == record.Timeout && result.Clock.Remaining(tc.wantWinner.Opponent()) != 0 {
+				t.Errorf("got clock %v at the end, want loser to have no time left", result.Clock)
+			}
+		})
+	}
+}
+
+func TestMatchTimePerTurnWithTimeControl(t *testing.T) {
+	slowPlayer := funcPlayer(func(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+		time.Sleep(30 * time.Millisecond)
+		return b.LegalMoves()[0], nil
+	})
+	result, err := referee.Match(context.Background(), slowPlayer, slowPlayer, referee.Options{
+		TimePerTurn: 10 * time.Millisecond,
+		TimeControl: referee.TimeControl{Budget: time.Minute},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Winner != ttt.O || result.Termination != record.Timeout {
+		t.Errorf("got winner %q by %v (%v), want %q by %v", result.Winner, result.Termination, result.Forfeit, ttt.O, record.Timeout)
+	}
+	// X took too long for its turn, but still had time left on its clock.
+	if got, want := result.Clock.X, time.Minute-10*time.Millisecond; got != want {
+		t.Errorf("got %v left on X's clock, want %v", got, want)
+	}
+}
+
+// clockDisplay is a display that reports the clock at the start of each turn.
+type clockDisplay func(referee.Clock)
+
+func (clockDisplay) GameStart(x, o ttt.Player)           {}
+func (d clockDisplay) TurnStart(t referee.Turn)          { d(t.Clock) }
+func (clockDisplay) TurnEnd(referee.Turn)                {}
+func (clockDisplay) GameEnd(r referee.Result, err error) {}
+
+// seededPlayer is a firstPlayer that reports a seed.
+type seededPlayer struct {
+	firstPlayer
+	seed int64
+}
+
+func (p seededPlayer) Seed() (seed int64, ok bool) { return p.seed, true }
+
+func TestRecorderSeeds(t *testing.T) {
+	var recorder referee.Recorder
+	result, err := referee.Match(context.Background(), seededPlayer{seed: 42}, firstPlayer{}, referee.Options{
+		Displays: []referee.Display{&recorder},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.XSeed == nil || *result.XSeed != 42 || result.OSeed != nil {
+		t.Errorf("got result seeds %v and %v, want 42 and none", result.XSeed, result.OSeed)
+	}
+	rec, _ := recorder.Record()
+	if rec.X.Seed == nil || *rec.X.Seed != 42 {
+		t.Errorf("got player X seed %v, want 42", rec.X.Seed)
+	}
+	if rec.O.Seed != nil {
+		t.Errorf("got player O seed %v, want none", *rec.O.Seed)
+	}
+}
@@ -0,0 +1,205 @@
+// Package registry is a registry of tic-tac-toe players.
+//
+// Player packages register themselves in their init function,
+// so importing a player package, even only for its side effects,
+// makes it available by name:
+//
+//	import _ "github.com/shurcooL/tictactoe/player/perfect"
+//
+//	p, err := registry.New("perfect", registry.Options{Difficulty: registry.Easy})
+package registry
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"sync"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+// Entry is a registered player.
+type Entry struct {
+	// Name the player is registered under, e.g., "random".
+	// It's used to choose the player, so it should be short and lower case.
+	Name string
+
+	// Description of the player, in a single sentence.
+	Description string
+
+	// Options the player supports.
+	Options []Option
+
+	// New creates a player with the given options.
+	// Only options in Options are ever set.
+	New func(Options) (ttt.Player, error)
+}
+
+// Supports reports whether the player supports option o.
+func (e Entry) Supports(o Option) bool {
+	for _, supported := range e.Options {
+		if supported == o {
+			return true
+		}
+	}
+	return false
+}
+
+// Option is an option that a player may support.
+type Option uint8
+
+// Options that a player may support.
+const (
+	SeedOption       Option = iota + 1 // Seed for the player's randomness.
+	DifficultyOption                   // How well the player plays.
+	NameOption                         // Name of the player.
+	ImageOption                        // Image of the player.
+	InstantOption                      // Whether the player moves without artificial delays.
+)
+
+func (o Option) String() string {
+	switch o {
+	case SeedOption:
+		return "seed"
+	case DifficultyOption:
+		return "difficulty"
+	case NameOption:
+		return "name"
+	case ImageOption:
+		return "image"
+	case InstantOption:
+		return "instant"
+	default:
+		panic("unreachable")
+	}
+}
+
+// Options for creating a player.
+// The zero value of each option means the player's default.
+type Options struct {
+	Seed       *int64       // Seed for the player's randomness, if not nil.
+	Difficulty Difficulty   // How well the player plays.
+	Name       string       // Name of the player.
+	Image      template.URL // Image of the player.
+	Instant    bool         // Move as soon as possible, without pretending to think.
+}
+
+// set returns the options that are set to a non-zero value.
+func (opt Options) set() []Option {
+	var set []Option
+	if opt.Seed != nil {
+		set = append(set, SeedOption)
+	}
+	if opt.Difficulty != 0 {
+		set = append(set, DifficultyOption)
+	}
+	if opt.Name != "" {
+		set = append(set, NameOption)
+	}
+	if opt.Image != "" {
+		set = append(set, ImageOption)
+	}
+	if opt.Instant {
+		set = append(set, InstantOption)
+	}
+	return set
+}
+
+// Difficulty is how well a player plays.
+type Difficulty uint8
+
+// Difficulties, from worst to best.
+const (
+	Easy Difficulty = iota + 1
+	Medium
+	Hard
+)
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	default:
+		panic("unreachable")
+	}
+}
+
+// ParseDifficulty parses a difficulty from its name, e.g., "easy".
+func ParseDifficulty(s string) (Difficulty, error) {
+	for d := Easy; d <= Hard; d++ {
+		if s == d.String() {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown difficulty %q, want one of: easy, medium, hard", s)
+}
+
+var (
+	mu      sync.RWMutex
+	entries = make(map[string]Entry)
+)
+
+// Register makes a player available by e.Name.
+// It panics if e is missing a name or a constructor,
+// or if a player with the same name is already registered.
+func Register(e Entry) {
+	mu.Lock()
+	defer mu.Unlock()
+	if e.Name == "" || e.New == nil {
+		panic("registry: Register called with a missing name or constructor")
+	}
+	if _, dup := entries[e.Name]; dup {
+		panic("registry: Register called twice for player " + e.Name)
+	}
+	entries[e.Name] = e
+}
+
+// Lookup returns the player registered under name, if any.
+func Lookup(name string) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	e, ok := entries[name]
+	return e, ok
+}
+
+// List returns all registered players, sorted by name.
+func List() []Entry {
+	mu.RLock()
+	defer mu.RUnlock()
+	var list []Entry
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Names returns the names of all registered players, sorted.
+func Names() []string {
+	var names []string
+	for _, e := range List() {
+		names = append(names, e.Name)
+	}
+	return names
+}
+
+// New creates the player registered under name with the given options.
+// It returns an error if there's no such player,
+// or if an option is set that the player doesn't support.
+func New(name string, opt Options) (ttt.Player, error) {
+	e, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown player %q, available players are: %v", name, strings.Join(Names(), ", "))
+	}
+	for _, o := range opt.set() {
+		if !e.Supports(o) {
+			return nil, fmt.Errorf("player %q doesn't support the %v option", name, o)
+		}
+	}
+	return e.New(opt)
+}
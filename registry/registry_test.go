@@ -0,0 +1,77 @@
+package registry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	_ "github.com/shurcooL/tictactoe/player/bad"
+	_ "github.com/shurcooL/tictactoe/player/human"
+	_ "github.com/shurcooL/tictactoe/player/perfect"
+	_ "github.com/shurcooL/tictactoe/player/random"
+	"github.com/shurcooL/tictactoe/registry"
+)
+
+func TestList(t *testing.T) {
+	got := registry.Names()
+	want := []string{"bad", "human", "perfect", "random"}
+	if len(got) != len(want) {
+		t.Fatalf("got players %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got players %v, want %v", got, want)
+		}
+	}
+	for _, e := range registry.List() {
+		if e.Description == "" {
+			t.Errorf("player %q has no description", e.Name)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	seed := int64(1)
+	p, err := registry.New("perfect", registry.Options{Seed: &seed, Difficulty: registry.Easy, Name: "Gopher"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.Name(), "Gopher"; got != want {
+		t.Errorf("got name %q, want %q", got, want)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := p.Play(ctx, ttt.Board{}, ttt.X); err != nil {
+		t.Error(err)
+	}
+
+	// Zero is a seed like any other.
+	zero := int64(0)
+	p, err = registry.New("random", registry.Options{Seed: &zero})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seed, ok := p.(ttt.Seeder).Seed(); !ok || seed != 0 {
+		t.Errorf("got seed %v (known: %v), want 0", seed, ok)
+	}
+
+	if _, err := registry.New("random", registry.Options{Difficulty: registry.Hard}); err == nil {
+		t.Error("got no error for an unsupported option")
+	}
+	if _, err := registry.New("nobody", registry.Options{}); err == nil {
+		t.Error("got no error for an unknown player")
+	}
+}
+
+func TestParseDifficulty(t *testing.T) {
+	for d := registry.Easy; d <= registry.Hard; d++ {
+		got, err := registry.ParseDifficulty(d.String())
+		if err != nil || got != d {
+			t.Errorf("ParseDifficulty(%q) = %v, %v; want %v", d.String(), got, err, d)
+		}
+	}
+	if _, err := registry.ParseDifficulty("impossible"); err == nil {
+		t.Error("got no error for an unknown difficulty")
+	}
+}
@@ -0,0 +1,120 @@
+package tictactoe
+
+// Transform is one of the 8 symmetries of the square board,
+// the rotations and reflections that map a board onto itself.
+type Transform uint8
+
+// Transforms of the board.
+const (
+	Identity            Transform = iota // Leave the board as is.
+	Rotate90                             // Rotate 90 degrees clockwise.
+	Rotate180                            // Rotate 180 degrees.
+	Rotate270                            // Rotate 270 degrees clockwise.
+	ReflectHorizontal                    // Reflect across the horizontal axis, swapping top and bottom rows.
+	ReflectVertical                      // Reflect across the vertical axis, swapping left and right columns.
+	ReflectDiagonal                      // Reflect across the diagonal from top-left to bottom-right.
+	ReflectAntiDiagonal                  // Reflect across the diagonal from top-right to bottom-left.
+)
+
+// Transforms are all symmetries of the board.
+var Transforms = [...]Transform{
+	Identity, Rotate90, Rotate180, Rotate270,
+	ReflectHorizontal, ReflectVertical, ReflectDiagonal, ReflectAntiDiagonal,
+}
+
+func (t Transform) String() string {
+	switch t {
+	case Identity:
+		return "identity"
+	case Rotate90:
+		return "rotate 90°"
+	case Rotate180:
+		return "rotate 180°"
+	case Rotate270:
+		return "rotate 270°"
+	case ReflectHorizontal:
+		return "reflect horizontally"
+	case ReflectVertical:
+		return "reflect vertically"
+	case ReflectDiagonal:
+		return "reflect diagonally"
+	case ReflectAntiDiagonal:
+		return "reflect anti-diagonally"
+	default:
+		panic("unreachable")
+	}
+}
+
+// Inverse returns the transform that undoes t.
+func (t Transform) Inverse() Transform {
+	switch t {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	default:
+		// All other transforms are their own inverse.
+		return t
+	}
+}
+
+// Transform returns the cell index that move m maps to under transform t.
+// m must be valid.
+func (m Move) Transform(t Transform) Move {
+	r, c := int(m)/3, int(m)%3
+	switch t {
+	case Identity:
+	case Rotate90:
+		r, c = c, 2-r
+	case Rotate180:
+		r, c = 2-r, 2-c
+	case Rotate270:
+		r, c = 2-c, r
+	case ReflectHorizontal:
+		r = 2 - r
+	case ReflectVertical:
+		c = 2 - c
+	case ReflectDiagonal:
+		r, c = c, r
+	case ReflectAntiDiagonal:
+		r, c = 2-c, 2-r
+	default:
+		panic("unreachable")
+	}
+	return Move(3*r + c)
+}
+
+// Transform returns board b transformed by transform t.
+func (b Board) Transform(t Transform) Board {
+	var tb Board
+	for i, cell := range b.Cells {
+		tb.Cells[Move(i).Transform(t)] = cell
+	}
+	return tb
+}
+
+// Canonical returns the canonical form of board b, the smallest board
+// among all of its symmetries, and a transform t that maps b to it.
+// Boards that are symmetric to each other have the same canonical form.
+//
+// Boards are ordered by comparing their cells in row major order,
+// with F < X < O.
+func (b Board) Canonical() (canonical Board, t Transform) {
+	canonical, t = b, Identity
+	for _, tr := range Transforms[1:] {
+		if tb := b.Transform(tr); less(tb, canonical) {
+			canonical, t = tb, tr
+		}
+	}
+	return canonical, t
+}
+
+// less reports whether board a is ordered before board b.
+func less(a, b Board) bool {
+	for i := range a.Cells {
+		if a.Cells[i] != b.Cells[i] {
+			return a.Cells[i] < b.Cells[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestMoveTransform(t *testing.T) {
+	// Where the top-left and top-middle cells go under each transform.
+	tests := []struct {
+		t           ttt.Transform
+		corner, top ttt.Move
+	}{
+		{ttt.Identity, 0, 1},
+		{ttt.Rotate90, 2, 5},
+		{ttt.Rotate180, 8, 7},
+		{ttt.Rotate270, 6, 3},
+		{ttt.ReflectHorizontal, 6, 7},
+		{ttt.ReflectVertical, 2, 1},
+		{ttt.ReflectDiagonal, 0, 3},
+		{ttt.ReflectAntiDiagonal, 8, 5},
+	}
+	for _, tc := range tests {
+		if got := ttt.Move(0).Transform(tc.t); got != tc.corner {
+			t.Errorf("%v: got corner %v, want %v", tc.t, got, tc.corner)
+		}
+		if got := ttt.Move(1).Transform(tc.t); got != tc.top {
+			t.Errorf("%v: got top %v, want %v", tc.t, got, tc.top)
+		}
+		for m := ttt.Move(0); m < 9; m++ {
+			if got := m.Transform(tc.t).Transform(tc.t.Inverse()); got != m {
+				t.Errorf("%v: move %v doesn't survive a round trip through the inverse, got %v", tc.t, m, got)
+			}
+		}
+	}
+}
+
+func TestBoardCanonical(t *testing.T) {
+	// An X in any corner is the same position.
+	var want ttt.Board
+	for i, m := range []ttt.Move{0, 2, 6, 8} {
+		var b ttt.Board
+		b.Cells[m] = ttt.X
+		canonical, tr := b.Canonical()
+		if i == 0 {
+			want = canonical
+		} else if canonical != want {
+			t.Errorf("corner %v: got canonical\n%v\nwant\n%v", m, canonical, want)
+		}
+		if got := b.Transform(tr); got != canonical {
+			t.Errorf("corner %v: transform %v doesn't map board to its canonical form", m, tr)
+		}
+		if got := canonical.Transform(tr.Inverse()); got != b {
+			t.Errorf("corner %v: inverse of transform %v doesn't map canonical form back to board", m, tr)
+		}
+	}
+
+	// There are 765 essentially different positions reachable in legal play.
+	positions := make(map[ttt.Board]bool)
+	for _, b := range allBoards() {
+		if _, err := b.NextMark(ttt.X); err != nil {
+			continue
+		}
+		canonical, _ := b.Canonical()
+		positions[canonical] = true
+	}
+	if got, want := len(positions), 765; got != want {
+		t.Errorf("got %v distinct positions, want %v", got, want)
+	}
+}
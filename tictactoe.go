@@ -4,6 +4,7 @@ package tictactoe
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
 )
@@ -17,9 +18,34 @@ type Player interface {
 	// for this player. Its mark is either X or O.
 	// ctx is expected to have a deadline set, and Play may take time
 	// to "think" until deadline is reached before returning.
+	//
+	// Instead of a move, Play may return ErrResign to resign the game,
+	// or ErrOfferDraw to offer the opponent a draw.
 	Play(ctx context.Context, b Board, mark State) (Move, error)
 }
 
+// ErrResign is returned by Player.Play to resign the game.
+// The opponent wins.
+var ErrResign = errors.New("resign")
+
+// ErrOfferDraw is returned by Player.Play to offer the opponent a draw
+// instead of moving. If the opponent accepts, the game ends in a draw.
+// Otherwise, Play is called again, and the player must move or resign.
+// A player may offer a draw at most once per turn.
+var ErrOfferDraw = errors.New("offer draw")
+
+// MNKPlayer is an optional interface implemented by players
+// that can play m,n,k-games with other than Classic rules,
+// on boards of any size. See NewMNKGame.
+//
+// In such games, the board is only given to PlayMNK.
+// Optional interfaces that take a Board, such as MoveObserver,
+// are given the zero Board.
+type MNKPlayer interface {
+	// PlayMNK is like Play, but takes an m,n,k-game board b.
+	PlayMNK(ctx context.Context, b MNKBoard, mark State) (Move, error)
+}
+
 // Imager is an optional interface implemented by players
 // that have an image that represents them.
 type Imager interface {
@@ -36,20 +62,109 @@ type CellClicker interface {
 	CellClick(index int)
 }
 
+// Commander is an optional interface implemented by players
+// that take commands other than moves from their user,
+// such as a human player.
+type Commander interface {
+	// Command is called when the user gives the player command c.
+	Command(c Command)
+}
+
+// Command that a user gives a player, other than a move.
+type Command uint8
+
+// Commands that a user can give a player.
+const (
+	Resign      Command = iota + 1 // Resign the game, on the player's turn.
+	OfferDraw                      // Offer the opponent a draw, on the player's turn.
+	AcceptDraw                     // Accept the opponent's draw offer.
+	DeclineDraw                    // Decline the opponent's draw offer.
+)
+
+func (c Command) String() string {
+	switch c {
+	case Resign:
+		return "resign"
+	case OfferDraw:
+		return "offer draw"
+	case AcceptDraw:
+		return "accept draw"
+	case DeclineDraw:
+		return "decline draw"
+	default:
+		panic("unreachable")
+	}
+}
+
+// GameStarter is an optional interface implemented by players
+// that wish to be notified when a new game starts.
+type GameStarter interface {
+	// StartGame is called before the first turn of a new game.
+	// mark is the player's mark in that game, and first is
+	// the mark of the player that moves first.
+	// It should return promptly, since the game waits for it.
+	StartGame(mark, first State)
+}
+
+// MoveObserver is an optional interface implemented by players
+// that wish to be notified about all moves made in a game,
+// including the opponent's.
+type MoveObserver interface {
+	// ObserveMove is called after a move is applied to the board.
+	// b is the board after the move, and mark is the mark
+	// of the player that made the move.
+	// It should return promptly, since the game waits for it.
+	ObserveMove(b Board, mark State, move Move)
+}
+
+// GameEnder is an optional interface implemented by players
+// that wish to be notified when a game is over.
+type GameEnder interface {
+	// EndGame is called when a game is over.
+	// b is the final board, and result is XWon, OWon or Tie,
+	// or NotEnd if the game was stopped before it ended.
+	// It should return promptly, since the game waits for it.
+	EndGame(b Board, result Condition)
+}
+
+// DrawAccepter is an optional interface implemented by players
+// that consider draw offers. Players that don't implement it
+// decline all draw offers.
+type DrawAccepter interface {
+	// AcceptDraw is called when the opponent offers a draw,
+	// and reports whether the player accepts it.
+	// b is the current board, and mark is this player's mark.
+	// ctx is expected to have a deadline set.
+	AcceptDraw(ctx context.Context, b Board, mark State) bool
+}
+
+// Seeder is an optional interface implemented by players
+// that make random choices, so that their games can be reproduced.
+type Seeder interface {
+	// Seed returns the seed of the player's source of randomness.
+	// ok is false if the seed isn't known,
+	// e.g., because the player was given a custom source.
+	//
+	// A new player created with the same seed
+	// plays the same moves in the same positions.
+	Seed() (seed int64, ok bool)
+}
+
 // Move is the board cell index where to place one's mark, a value in range [0, 9).
 //
 // A move is valid if it's in the range [0, 9).
 // A move is legal if it can be applied to a given board configuration.
+//
+// On an m,n,k-game board, a move is valid if it's in the range [0, Width*Height),
+// see Rules.ValidMove.
+//
+// In JSON, a move is encoded as a number.
 type Move int
 
 // Valid reports if the move is valid.
 // A valid move may not be legal depending on the board configuration.
 func (m Move) Valid() error {
-	ok := m >= 0 && m < 9
-	if !ok {
-		return fmt.Errorf("move %v is out of range [0, 9)", m)
-	}
-	return nil
+	return Classic.ValidMove(m)
 }
 
 // State of a board cell.
@@ -75,6 +190,19 @@ func (s State) String() string {
 	}
 }
 
+// Opponent returns the mark of the opponent of the player with mark s.
+// s must be either X or O.
+func (s State) Opponent() State {
+	switch s {
+	case X:
+		return O
+	case O:
+		return X
+	default:
+		panic(fmt.Sprintf("tictactoe: State(%d) has no opponent", s))
+	}
+}
+
 // Condition of the board configuration.
 type Condition uint8
 
@@ -84,6 +212,7 @@ const (
 	XWon
 	OWon
 	Tie
+	Invalid // Board position can't be reached in legal play. See Board.Validate.
 )
 
 func (c Condition) String() string {
@@ -96,6 +225,8 @@ func (c Condition) String() string {
 		return "player O won"
 	case Tie:
 		return "tie"
+	case Invalid:
+		return "invalid position"
 	default:
 		panic("unreachable")
 	}
@@ -126,44 +257,13 @@ func (b *Board) Apply(move Move, mark State) error {
 
 // Condition returns the condition of the board.
 func (b Board) Condition() Condition {
-	var (
-		x = (b.Cells[0] == X && b.Cells[1] == X && b.Cells[2] == X) || // Check all rows.
-			(b.Cells[3] == X && b.Cells[4] == X && b.Cells[5] == X) ||
-			(b.Cells[6] == X && b.Cells[7] == X && b.Cells[8] == X) ||
-
-			(b.Cells[0] == X && b.Cells[3] == X && b.Cells[6] == X) || // Check all columns.
-			(b.Cells[1] == X && b.Cells[4] == X && b.Cells[7] == X) ||
-			(b.Cells[2] == X && b.Cells[5] == X && b.Cells[8] == X) ||
-
-			(b.Cells[0] == X && b.Cells[4] == X && b.Cells[8] == X) || // Check all diagonals.
-			(b.Cells[2] == X && b.Cells[4] == X && b.Cells[6] == X)
-
-		o = (b.Cells[0] == O && b.Cells[1] == O && b.Cells[2] == O) || // Check all rows.
-			(b.Cells[3] == O && b.Cells[4] == O && b.Cells[5] == O) ||
-			(b.Cells[6] == O && b.Cells[7] == O && b.Cells[8] == O) ||
-
-			(b.Cells[0] == O && b.Cells[3] == O && b.Cells[6] == O) || // Check all columns.
-			(b.Cells[1] == O && b.Cells[4] == O && b.Cells[7] == O) ||
-			(b.Cells[2] == O && b.Cells[5] == O && b.Cells[8] == O) ||
-
-			(b.Cells[0] == O && b.Cells[4] == O && b.Cells[8] == O) || // Check all diagonals.
-			(b.Cells[2] == O && b.Cells[4] == O && b.Cells[6] == O)
-
-		freeCellsLeft = b.Cells[0] == F || b.Cells[1] == F || b.Cells[2] == F ||
-			b.Cells[3] == F || b.Cells[4] == F || b.Cells[5] == F ||
-			b.Cells[6] == F || b.Cells[7] == F || b.Cells[8] == F
-	)
-
-	switch {
-	case x && !o:
-		return XWon
-	case o && !x:
-		return OWon
-	case !freeCellsLeft:
-		return Tie
-	default:
-		return NotEnd
-	}
+	return b.mnk().Condition()
+}
+
+// mnk returns board b as an m,n,k-game board with Classic rules.
+// The returned board shares b's cells.
+func (b *Board) mnk() MNKBoard {
+	return MNKBoard{Rules: Classic, Cells: b.Cells[:]}
 }
 
 func (b Board) String() string {
@@ -0,0 +1,107 @@
+package tournament
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteText writes the standings and crosstable as an aligned text table.
+// Crosstable columns are numbered by rank, and each cell
+// is the points the row's entrant scored against that opponent.
+func (r Results) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range r.table() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteCSV writes the standings and crosstable as CSV,
+// with the same columns as WriteText.
+func (r Results) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.WriteAll(r.table())
+	return cw.Error()
+}
+
+// table returns the standings and crosstable as rows of cells,
+// starting with a header row.
+func (r Results) table() [][]string {
+	standings := r.Standings()
+	header := []string{"Rank", "Player", "Played", "W", "L", "D", "Forfeits", "Points"}
+	for i := range standings {
+		header = append(header, strconv.Itoa(i+1))
+	}
+	rows := [][]string{header}
+	for i, s := range standings {
+		row := []string{
+			strconv.Itoa(i + 1),
+			s.Name,
+			strconv.Itoa(s.Played()),
+			strconv.Itoa(s.Wins),
+			strconv.Itoa(s.Losses),
+			strconv.Itoa(s.Draws),
+			strconv.Itoa(s.Forfeits),
+			formatPoints(s.Points()),
+		}
+		for _, opponent := range standings {
+			score, ok := s.Against[opponent.Name]
+			switch {
+			case opponent.Name == s.Name:
+				row = append(row, "-")
+			case !ok:
+				row = append(row, "")
+			default:
+				row = append(row, formatPoints(score.Points()))
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteJSON writes the standings, including the crosstable,
+// as an indented JSON object.
+func (r Results) WriteJSON(w io.Writer) error {
+	type standing struct {
+		Rank     int              `json:"rank"`
+		Name     string           `json:"name"`
+		Played   int              `json:"played"`
+		Wins     int              `json:"wins"`
+		Losses   int              `json:"losses"`
+		Draws    int              `json:"draws"`
+		Forfeits int              `json:"forfeits"`
+		Points   float64          `json:"points"`
+		Against  map[string]Score `json:"against"` // Score against each opponent, by name.
+	}
+	v := struct {
+		Games     int        `json:"games"`
+		Standings []standing `json:"standings"`
+	}{Games: len(r.Games)}
+	for i, s := range r.Standings() {
+		v.Standings = append(v.Standings, standing{
+			Rank:     i + 1,
+			Name:     s.Name,
+			Played:   s.Played(),
+			Wins:     s.Wins,
+			Losses:   s.Losses,
+			Draws:    s.Draws,
+			Forfeits: s.Forfeits,
+			Points:   s.Points(),
+			Against:  s.Against,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(v)
+}
+
+// formatPoints formats points without trailing zeros, e.g., "1.5" or "2".
+func formatPoints(p float64) string {
+	return strconv.FormatFloat(p, 'f', -1, 64)
+}
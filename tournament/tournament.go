@@ -0,0 +1,250 @@
+// Package tournament runs round-robin tournaments between tic-tac-toe players.
+//
+// Every entrant plays every other entrant the same number of games,
+// alternating colors, and the entrants are ranked by points:
+// 1 for a win, ½ for a draw and 0 for a loss.
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/referee"
+)
+
+// Entrant in a tournament.
+type Entrant struct {
+	// Name of the entrant. It must be unique within a tournament.
+	Name string
+
+	// New creates the entrant's player for a single game,
+	// seeded with seed if it makes random choices.
+	// A new player is created for every game, so that players
+	// don't need to be safe for concurrent use.
+	New func(seed int64) (ttt.Player, error)
+}
+
+// Options for a tournament.
+type Options struct {
+	// Games is the number of games each pair of entrants plays.
+	// Entrants alternate playing X, who moves first. Zero means 2.
+	Games int
+
+	// Concurrency is the maximum number of games played at once.
+	// Zero means runtime.NumCPU().
+	Concurrency int
+
+	// TimePerTurn and TimeControl are the time limits of each game.
+	// See referee.Options.
+	TimePerTurn time.Duration
+	TimeControl referee.TimeControl
+
+	// Seed is where the seeds of the entrants' players are derived from,
+	// so that a tournament with the same seed plays the same games.
+	// In the n'th game of the schedule, counting from 0, player X
+	// is created with seed Seed+2n and player O with seed Seed+2n+1.
+	Seed int64
+
+	// Progress, if not nil, is called after each game with its result.
+	// Calls are never made concurrently.
+	Progress func(Game)
+}
+
+// Game played in a tournament.
+type Game struct {
+	X, O   string         // Names of the entrants that played X and O.
+	Result referee.Result // Result of the game.
+}
+
+// Results of a tournament.
+type Results struct {
+	Entrants []string // Names of the entrants, in the order they were given.
+	Games    []Game   // Games that were played, in schedule order.
+}
+
+// Run plays a round-robin tournament between entrants.
+// It returns an error if two entrants have the same name.
+//
+// If ctx is done before all games are played, or an entrant's player
+// can't be created, Run returns the results of the games played so far
+// along with the error.
+func Run(ctx context.Context, entrants []Entrant, opt Options) (Results, error) {
+	if opt.Games == 0 {
+		opt.Games = 2
+	}
+	if opt.Concurrency == 0 {
+		opt.Concurrency = runtime.NumCPU()
+	}
+
+	results := Results{}
+	seen := make(map[string]bool)
+	for _, e := range entrants {
+		if seen[e.Name] {
+			return Results{}, fmt.Errorf("duplicate entrant %q", e.Name)
+		}
+		seen[e.Name] = true
+		results.Entrants = append(results.Entrants, e.Name)
+	}
+
+	// Schedule every pair of entrants to play opt.Games games, alternating colors.
+	type pairing struct{ x, o Entrant }
+	var schedule []pairing
+	for i := range entrants {
+		for j := i + 1; j < len(entrants); j++ {
+			for g := 0; g < opt.Games; g++ {
+				p := pairing{x: entrants[i], o: entrants[j]}
+				if g%2 == 1 {
+					p.x, p.o = p.o, p.x
+				}
+				schedule = append(schedule, p)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, opt.Concurrency)
+		mu       sync.Mutex // Guards played, firstErr and calls to opt.Progress.
+		played   = make([]bool, len(schedule))
+		firstErr error
+	)
+	games := make([]Game, len(schedule))
+	for i, p := range schedule {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int, p pairing) {
+			defer func() { <-sem; wg.Done() }()
+			g, err := play(ctx, p.x, p.o, opt.Seed+2*int64(i), opt)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			games[i], played[i] = g, true
+			if opt.Progress != nil {
+				opt.Progress(g)
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for i, g := range games {
+		if played[i] {
+			results.Games = append(results.Games, g)
+		}
+	}
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return results, firstErr
+}
+
+// play plays a single game between entrants x and o,
+// whose players are created with seeds seed and seed+1, respectively.
+func play(ctx context.Context, x, o Entrant, seed int64, opt Options) (Game, error) {
+	px, err := x.New(seed)
+	if err != nil {
+		return Game{}, err
+	}
+	po, err := o.New(seed + 1)
+	if err != nil {
+		return Game{}, err
+	}
+	result, err := referee.Match(ctx, px, po, referee.Options{
+		TimePerTurn: opt.TimePerTurn,
+		TimeControl: opt.TimeControl,
+		First:       ttt.X,
+	})
+	if err != nil {
+		return Game{}, err
+	}
+	return Game{X: x.Name, O: o.Name, Result: result}, nil
+}
+
+// Score of an entrant in a number of games.
+type Score struct {
+	Wins   int `json:"wins"`
+	Losses int `json:"losses"`
+	Draws  int `json:"draws"`
+}
+
+// Played returns the number of games played.
+func (s Score) Played() int { return s.Wins + s.Losses + s.Draws }
+
+// Points returns the number of points scored:
+// 1 for a win, ½ for a draw and 0 for a loss.
+func (s Score) Points() float64 { return float64(s.Wins) + float64(s.Draws)/2 }
+
+// Standing of an entrant in a tournament.
+type Standing struct {
+	Name string
+	Score
+	Forfeits int              // Number of games lost by forfeit.
+	Against  map[string]Score // Score against each opponent, by name.
+}
+
+// Standings returns the standings of the entrants,
+// ranked by points, then by wins, then by name.
+func (r Results) Standings() []Standing {
+	standings := make(map[string]*Standing)
+	for _, name := range r.Entrants {
+		standings[name] = &Standing{Name: name, Against: make(map[string]Score)}
+	}
+	for _, g := range r.Games {
+		x, o := standings[g.X], standings[g.O]
+		vsO, vsX := x.Against[g.O], o.Against[g.X]
+		switch g.Result.Outcome() {
+		case ttt.XWon:
+			x.Wins, vsO.Wins = x.Wins+1, vsO.Wins+1
+			o.Losses, vsX.Losses = o.Losses+1, vsX.Losses+1
+			if g.Result.Termination.Forfeit() {
+				o.Forfeits++
+			}
+		case ttt.OWon:
+			o.Wins, vsX.Wins = o.Wins+1, vsX.Wins+1
+			x.Losses, vsO.Losses = x.Losses+1, vsO.Losses+1
+			if g.Result.Termination.Forfeit() {
+				x.Forfeits++
+			}
+		case ttt.Tie:
+			x.Draws, vsO.Draws = x.Draws+1, vsO.Draws+1
+			o.Draws, vsX.Draws = o.Draws+1, vsX.Draws+1
+		}
+		x.Against[g.O], o.Against[g.X] = vsO, vsX
+	}
+
+	var ranked []Standing
+	for _, name := range r.Entrants {
+		ranked = append(ranked, *standings[name])
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch {
+		case a.Points() != b.Points():
+			return a.Points() > b.Points()
+		case a.Wins != b.Wins:
+			return a.Wins > b.Wins
+		default:
+			return a.Name < b.Name
+		}
+	})
+	return ranked
+}
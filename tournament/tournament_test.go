@@ -0,0 +1,154 @@
+package tournament_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+	"github.com/shurcooL/tictactoe/tournament"
+)
+
+// firstPlayer always plays the first legal move.
+type firstPlayer struct{}
+
+func (firstPlayer) Name() string { return "First Player" }
+
+func (firstPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	return b.LegalMoves()[0], nil
+}
+
+// errorPlayer can't make a move, so it forfeits every game.
+type errorPlayer struct{}
+
+func (errorPlayer) Name() string { return "Error Player" }
+
+func (errorPlayer) Play(ctx context.Context, b ttt.Board, mark ttt.State) (ttt.Move, error) {
+	return 0, fmt.Errorf("no idea")
+}
+
+func entrant(name string, p ttt.Player) tournament.Entrant {
+	return tournament.Entrant{Name: name, New: func(int64) (ttt.Player, error) { return p, nil }}
+}
+
+func TestRun(t *testing.T) {
+	entrants := []tournament.Entrant{
+		entrant("error", errorPlayer{}),
+		entrant("first-a", firstPlayer{}),
+		entrant("first-b", firstPlayer{}),
+	}
+	var progress int
+	results, err := tournament.Run(context.Background(), entrants, tournament.Options{
+		Games:    4,
+		Progress: func(tournament.Game) { progress++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 pairs, 4 games each.
+	if got, want := len(results.Games), 12; got != want || progress != want {
+		t.Errorf("got %v games and %v progress calls, want %v", got, progress, want)
+	}
+
+	standings := results.Standings()
+	// The first player to move wins when both always play the first legal move,
+	// so with alternating colors the first-* players split their games evenly.
+	want := []struct {
+		name                          string
+		wins, losses, draws, forfeits int
+	}{
+		{"first-a", 6, 2, 0, 0},
+		{"first-b", 6, 2, 0, 0},
+		{"error", 0, 8, 0, 8},
+	}
+	for i, w := range want {
+		s := standings[i]
+		if s.Name != w.name || s.Wins != w.wins || s.Losses != w.losses || s.Draws != w.draws || s.Forfeits != w.forfeits {
+			t.Errorf("standing %v: got %+v, want %+v", i+1, s, w)
+		}
+	}
+	if got, want := standings[0].Against["first-b"], (tournament.Score{Wins: 2, Losses: 2}); got != want {
+		t.Errorf("got first-a against first-b %+v, want %+v", got, want)
+	}
+
+	var text, csv, js bytes.Buffer
+	if err := results.WriteText(&text); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text.String(), "first-a") || strings.Count(text.String(), "\n") != 4 {
+		t.Errorf("unexpected text output:\n%s", text.String())
+	}
+	if err := results.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.SplitN(csv.String(), "\n", 2)[0], "Rank,Player,Played,W,L,D,Forfeits,Points,1,2,3"; got != want {
+		t.Errorf("got CSV header %q, want %q", got, want)
+	}
+	if err := results.WriteJSON(&js); err != nil {
+		t.Fatal(err)
+	}
+	var v struct {
+		Games     int
+		Standings []struct {
+			Name   string
+			Points float64
+		}
+	}
+	if err := json.Unmarshal(js.Bytes(), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Games != 12 || len(v.Standings) != 3 || v.Standings[0].Points != 6 {
+		t.Errorf("unexpected JSON output:\n%s", js.String())
+	}
+}
+
+// seededPlayer plays the first legal move, and reports the seed it was created with.
+type seededPlayer struct {
+	firstPlayer
+	seed int64
+}
+
+func (p seededPlayer) Seed() (int64, bool) { return p.seed, true }
+
+func TestRunSeeds(t *testing.T) {
+	seeded := func(name string) tournament.Entrant {
+		return tournament.Entrant{Name: name, New: func(seed int64) (ttt.Player, error) { return seededPlayer{seed: seed}, nil }}
+	}
+	results, err := tournament.Run(context.Background(), []tournament.Entrant{seeded("a"), seeded("b"), seeded("c")}, tournament.Options{
+		Seed: 100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for n, g := range results.Games {
+		x, o := g.Result.XSeed, g.Result.OSeed
+		if x == nil || o == nil || *x != 100+2*int64(n) || *o != *x+1 {
+			t.Errorf("game %v: got seeds %v and %v, want %v and %v", n, x, o, 100+2*n, 100+2*n+1)
+		}
+	}
+}
+
+func TestRunDuplicateNames(t *testing.T) {
+	_, err := tournament.Run(context.Background(), []tournament.Entrant{
+		entrant("first", firstPlayer{}),
+		entrant("first", firstPlayer{}),
+	}, tournament.Options{})
+	if err == nil {
+		t.Error("got no error for entrants with the same name")
+	}
+}
+
+func TestRunCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := tournament.Run(ctx, []tournament.Entrant{
+		entrant("a", firstPlayer{}),
+		entrant("b", firstPlayer{}),
+	}, tournament.Options{})
+	if err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,80 @@
+package tictactoe
+
+// NextMark returns the mark of the player whose turn it is on board b,
+// given that the player with mark first, either X or O, moved first.
+// It returns an *InvalidPositionError if b isn't a position
+// that can be reached in legal play with first moving first.
+//
+// If the game is over, NextMark returns the mark of the player
+// who would've moved next.
+func (b Board) NextMark(first State) (State, error) {
+	return b.mnk().NextMark(first)
+}
+
+// NextMark returns the mark of the player whose turn it is on board b,
+// given that the player with mark first, either X or O, moved first.
+// It returns an *InvalidPositionError if b isn't a position
+// that can be reached in legal play with first moving first.
+//
+// If the game is over, NextMark returns the mark of the player
+// who would've moved next.
+func (b MNKBoard) NextMark(first State) (State, error) {
+	return nextMark(b.Cells, b.WinningLines(), b.K, first)
+}
+
+func nextMark(cells []State, lines []Line, k int, first State) (State, error) {
+	if err := validate(cells, lines, k); err != nil {
+		return F, err
+	}
+	second := first.Opponent()
+	var next State
+	switch count(cells, first) - count(cells, second) {
+	case 0:
+		next = first
+	case 1:
+		next = second
+	default:
+		// The second player has one more mark than the first player.
+		if second == X {
+			return F, &InvalidPositionError{Reason: TooManyX}
+		}
+		return F, &InvalidPositionError{Reason: TooManyO}
+	}
+	// The winner must have been the last to move.
+	if len(lines) > 0 && lines[0].Mark == next {
+		return F, &InvalidPositionError{Reason: WinnerNotLast}
+	}
+	return next, nil
+}
+
+// count returns the number of cells with the given mark.
+func count(cells []State, mark State) int {
+	var n int
+	for _, cell := range cells {
+		if cell == mark {
+			n++
+		}
+	}
+	return n
+}
+
+// LegalMoves returns all legal moves on board b, in increasing order.
+func (b Board) LegalMoves() []Move {
+	return b.mnk().LegalMoves()
+}
+
+// LegalMoves returns all legal moves on board b, in increasing order.
+func (b MNKBoard) LegalMoves() []Move {
+	return legalMoves(b.Cells)
+}
+
+func legalMoves(cells []State) []Move {
+	var moves []Move
+	for i, cell := range cells {
+		if cell != F {
+			continue
+		}
+		moves = append(moves, Move(i))
+	}
+	return moves
+}
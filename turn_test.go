@@ -0,0 +1,58 @@
+package tictactoe_test
+
+import (
+	"reflect"
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestBoardNextMark(t *testing.T) {
+	const (
+		x = ttt.X
+		o = ttt.O
+		f = ttt.F
+	)
+	tests := []struct {
+		board   ttt.Board
+		first   ttt.State
+		want    ttt.State
+		wantErr bool
+	}{
+		{board: ttt.Board{}, first: x, want: x},
+		{board: ttt.Board{}, first: o, want: o},
+		{board: ttt.Board{Cells: [9]ttt.State{x, f, f, f, f, f, f, f, f}}, first: x, want: o},
+		{board: ttt.Board{Cells: [9]ttt.State{x, f, f, f, f, f, f, f, f}}, first: o, wantErr: true},
+		{board: ttt.Board{Cells: [9]ttt.State{x, o, f, f, f, f, f, f, f}}, first: o, want: o},
+		// X won, and moved last.
+		{board: ttt.Board{Cells: [9]ttt.State{x, x, x, o, o, f, f, f, f}}, first: x, want: o},
+		// X won, but O would've had to move after that.
+		{board: ttt.Board{Cells: [9]ttt.State{x, x, x, o, o, o, f, f, f}}, first: x, wantErr: true},
+		{board: ttt.Board{Cells: [9]ttt.State{x, x, x, o, o, f, o, f, f}}, first: o, want: o},
+		{board: ttt.Board{Cells: [9]ttt.State{x, x, x, o, o, f, o, f, f}}, first: x, wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := tc.board.NextMark(tc.first)
+		if gotErr := err != nil; gotErr != tc.wantErr {
+			t.Errorf("board\n%v\nfirst %v: got error %v, want error %v", tc.board, tc.first, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("board\n%v\nfirst %v: got %v, want %v", tc.board, tc.first, got, tc.want)
+		}
+	}
+}
+
+func TestBoardLegalMoves(t *testing.T) {
+	b := ttt.Board{
+		Cells: [9]ttt.State{
+			ttt.X, ttt.F, ttt.O,
+			ttt.F, ttt.X, ttt.F,
+			ttt.O, ttt.F, ttt.F,
+		},
+	}
+	want := []ttt.Move{1, 3, 5, 7, 8}
+	if got := b.LegalMoves(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,124 @@
+package tictactoe
+
+import "fmt"
+
+// Reason why a board position can't be reached in legal play.
+type Reason uint8
+
+// Reasons why a board position can't be reached in legal play.
+const (
+	TooManyX      Reason = iota + 1 // X has more than one mark more than O.
+	TooManyO                        // O has more than one mark more than X.
+	BothWon                         // Both X and O have winning lines.
+	WinnerNotLast                   // The winner has fewer marks than the loser, so the loser moved after the game was over.
+	DisjointLines                   // The winner has winning lines that couldn't have been completed by a single move.
+)
+
+func (r Reason) String() string {
+	switch r {
+	case TooManyX:
+		return "X has too many marks"
+	case TooManyO:
+		return "O has too many marks"
+	case BothWon:
+		return "both X and O have winning lines"
+	case WinnerNotLast:
+		return "the loser moved after the game was over"
+	case DisjointLines:
+		return "winning lines couldn't have been completed by a single move"
+	default:
+		panic("unreachable")
+	}
+}
+
+// InvalidPositionError is returned when a board position
+// can't be reached in legal play.
+type InvalidPositionError struct {
+	Reason Reason
+}
+
+func (e *InvalidPositionError) Error() string {
+	return fmt.Sprintf("invalid position: %v", e.Reason)
+}
+
+// Validate reports whether board b is a position that can be reached
+// in legal play, with either player moving first.
+// If it can't, an *InvalidPositionError is returned.
+func (b Board) Validate() error {
+	return b.mnk().Validate()
+}
+
+// Validate reports whether board b is a position that can be reached
+// in legal play, with either player moving first.
+// If it can't, an *InvalidPositionError is returned.
+func (b MNKBoard) Validate() error {
+	return validate(b.Cells, b.WinningLines(), b.K)
+}
+
+// validate reports whether a board with the given cells and winning lines
+// can be reached in legal play, where k marks in a row win.
+func validate(cells []State, lines []Line, k int) error {
+	var x, o int
+	for _, cell := range cells {
+		switch cell {
+		case X:
+			x++
+		case O:
+			o++
+		}
+	}
+	switch {
+	case x > o+1:
+		return &InvalidPositionError{Reason: TooManyX}
+	case o > x+1:
+		return &InvalidPositionError{Reason: TooManyO}
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+	winner := lines[0].Mark
+	for _, l := range lines[1:] {
+		if l.Mark != winner {
+			return &InvalidPositionError{Reason: BothWon}
+		}
+	}
+	if (winner == X && x < o) || (winner == O && o < x) {
+		return &InvalidPositionError{Reason: WinnerNotLast}
+	}
+	// The game ended as soon as the first line was completed,
+	// so the winner's last move must be a part of every winning line.
+	if !lastMovePossible(lines, k) {
+		return &InvalidPositionError{Reason: DisjointLines}
+	}
+	return nil
+}
+
+// lastMovePossible reports whether there's a cell that's a part of all lines,
+// such that none of the lines had k marks in a row before a mark was placed there.
+func lastMovePossible(lines []Line, k int) bool {
+	for _, m := range lines[0].Cells {
+		possible := true
+		for _, l := range lines {
+			i := index(l.Cells, m)
+			if i == -1 || i >= k || len(l.Cells)-1-i >= k {
+				possible = false
+				break
+			}
+		}
+		if possible {
+			return true
+		}
+	}
+	return false
+}
+
+// index returns the index of m in cells, or -1 if it's not there.
+func index(cells []Move, m Move) int {
+	for i, c := range cells {
+		if c == m {
+			return i
+		}
+	}
+	return -1
+}
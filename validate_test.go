@@ -0,0 +1,104 @@
+package tictactoe_test
+
+import (
+	"testing"
+
+	ttt "github.com/shurcooL/tictactoe"
+)
+
+func TestBoardValidate(t *testing.T) {
+	const (
+		x = ttt.X
+		o = ttt.O
+		f = ttt.F
+	)
+	tests := []struct {
+		name  string
+		board ttt.Board
+		want  ttt.Reason // Zero means valid.
+	}{
+		{
+			name:  "empty",
+			board: ttt.Board{},
+		},
+		{
+			name:  "O moved first",
+			board: ttt.Board{Cells: [9]ttt.State{o, f, f, f, f, f, f, f, f}},
+		},
+		{
+			name:  "double win by one move",
+			board: ttt.Board{Cells: [9]ttt.State{x, x, x, x, o, o, x, o, o}},
+		},
+		{
+			name:  "too many X",
+			board: ttt.Board{Cells: [9]ttt.State{x, x, x, x, x, f, f, f, f}},
+			want:  ttt.TooManyX,
+		},
+		{
+			name:  "too many O",
+			board: ttt.Board{Cells: [9]ttt.State{o, o, f, f, f, f, f, f, f}},
+			want:  ttt.TooManyO,
+		},
+		{
+			name:  "both won",
+			board: ttt.Board{Cells: [9]ttt.State{x, x, x, f, f, f, o, o, o}},
+			want:  ttt.BothWon,
+		},
+		{
+			name:  "O moved after X won",
+			board: ttt.Board{Cells: [9]ttt.State{x, x, x, o, o, f, o, o, f}},
+			want:  ttt.WinnerNotLast,
+		},
+		{
+			name:  "parallel lines",
+			board: ttt.Board{Cells: [9]ttt.State{x, o, x, x, o, x, x, o, x}},
+			want:  ttt.TooManyX,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.board.Validate()
+			switch {
+			case tc.want == 0 && err != nil:
+				t.Errorf("got error %v, want nil", err)
+			case tc.want != 0 && err == nil:
+				t.Errorf("got nil error, want %v", tc.want)
+			case tc.want != 0:
+				if got := err.(*ttt.InvalidPositionError).Reason; got != tc.want {
+					t.Errorf("got reason %v, want %v", got, tc.want)
+				}
+				if got := tc.board.Condition(); got != ttt.Invalid {
+					t.Errorf("got condition %v, want %v", got, ttt.Invalid)
+				}
+			}
+		})
+	}
+}
+
+func TestMNKBoardValidateDisjointLines(t *testing.T) {
+	// X has two lines on separate rows, which no single move could've completed.
+	b, err := ttt.NewMNKBoard(ttt.Rules{Width: 4, Height: 4, K: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range []ttt.Move{0, 1, 2, 8, 9, 10} {
+		b.Cells[m] = ttt.X
+	}
+	for _, m := range []ttt.Move{4, 5, 7, 12, 15} {
+		b.Cells[m] = ttt.O
+	}
+	err = b.Validate()
+	if e, ok := err.(*ttt.InvalidPositionError); !ok || e.Reason != ttt.DisjointLines {
+		t.Errorf("got error %v, want %v", err, ttt.DisjointLines)
+	}
+}
+
+// TestBoardConditionInvalid checks that Condition reports Invalid
+// exactly for the boards that Validate rejects.
+func TestBoardConditionInvalid(t *testing.T) {
+	for _, b := range allBoards() {
+		if got, want := b.Condition() == ttt.Invalid, b.Validate() != nil; got != want {
+			t.Errorf("board\n%v\ngot condition %v, want invalid %v", b, b.Condition(), want)
+		}
+	}
+}